@@ -0,0 +1,33 @@
+package gotenberg
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrConversionCacheMiss happens when no artifact is stored under the
+// requested cache key.
+var ErrConversionCacheMiss = errors.New("conversion cache miss")
+
+// ConversionCache stores the output of a conversion, keyed on a digest of
+// its input and options, so that an identical request can be served without
+// re-running the underlying conversion engine.
+type ConversionCache interface {
+	// Get returns the paths previously stored under key. It returns
+	// ErrConversionCacheMiss if nothing is stored, or if the entry expired.
+	Get(key string) ([]string, error)
+
+	// Put stores paths under key with the given time-to-live. A zero ttl
+	// means the entry never expires on its own (a backend with an LRU
+	// policy, such as the filesystem one, can still evict it).
+	Put(key string, paths []string, ttl time.Duration) error
+
+	// Delete removes the entry stored under key, if any.
+	Delete(key string) error
+}
+
+// ConversionCacheProvider is implemented by modules exposing a
+// ConversionCache, mirroring PDFEngineProvider/JobStoreProvider.
+type ConversionCacheProvider interface {
+	ConversionCache() (ConversionCache, error)
+}
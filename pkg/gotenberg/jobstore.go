@@ -0,0 +1,46 @@
+package gotenberg
+
+import (
+	"errors"
+	"time"
+)
+
+// JobStatus describes the lifecycle of an async job tracked by a JobStore.
+type JobStatus string
+
+const (
+	JobStatusPending JobStatus = "pending"
+	JobStatusRunning JobStatus = "running"
+	JobStatusDone    JobStatus = "done"
+	JobStatusFailed  JobStatus = "failed"
+)
+
+// Job is the durable record of an asynchronously-submitted conversion.
+type Job struct {
+	ID          string
+	TenantID    string
+	Status      JobStatus
+	ResultPaths []string
+	Error       string
+	WebhookURL  string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// ErrJobNotFound happens when no job matches the requested ID in a
+// JobStore.
+var ErrJobNotFound = errors.New("job not found")
+
+// JobStore persists Job records across the lifetime of an async request,
+// and across restarts for implementations backed by Redis or BoltDB.
+type JobStore interface {
+	Save(job Job) error
+	Get(id string) (Job, error)
+	Delete(id string) error
+}
+
+// JobStoreProvider is implemented by modules exposing a JobStore, mirroring
+// PDFEngineProvider/PDFPostProcessorProvider.
+type JobStoreProvider interface {
+	JobStore() (JobStore, error)
+}
@@ -0,0 +1,30 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+// PDFPostProcessor processes a single PDF file, producing another PDF file
+// at outputPath. Implementations are expected to be stateless and safe for
+// concurrent use, as a single request may run several steps in sequence
+// against different files.
+type PDFPostProcessor interface {
+	// Process applies the post-processing step described by params to the
+	// PDF at inputPath and writes the result to outputPath.
+	Process(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, params map[string]string) error
+}
+
+// PDFPostProcessorProvider is implemented by modules which expose one or
+// several named PDFPostProcessor so that routes can resolve a pipeline of
+// steps via dependency injection, similarly to how PDFEngineProvider
+// exposes PDFEngine.
+type PDFPostProcessorProvider interface {
+	PDFPostProcessor(name string) (PDFPostProcessor, error)
+}
+
+// ErrPDFPostProcessorNotAvailable happens when no module provides the
+// requested post-processing step.
+var ErrPDFPostProcessorNotAvailable = errors.New("no module provides the requested PDF post-processor")
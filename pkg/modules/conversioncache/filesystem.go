@@ -0,0 +1,210 @@
+package conversioncache
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+)
+
+// filesystemCache is the default gotenberg.ConversionCache backend: it
+// copies the cached files into dir and evicts the least-recently-used
+// entries once maxSize is exceeded.
+type filesystemCache struct {
+	dir     string
+	maxSize int64
+
+	mu          sync.Mutex
+	currentSize int64
+	lru         *list.List
+	entries     map[string]*list.Element
+}
+
+type filesystemCacheEntry struct {
+	key       string
+	dir       string
+	paths     []string
+	size      int64
+	expiresAt time.Time
+}
+
+func newFilesystemCache(dir string, maxSize int64) (*filesystemCache, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("create cache directory: %w", err)
+	}
+
+	return &filesystemCache{
+		dir:     dir,
+		maxSize: maxSize,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}, nil
+}
+
+// filesystemCacheLocalRetention bounds how long the private copies Get
+// makes on every cache hit are kept around before being swept, mirroring
+// s3CacheLocalRetention.
+const filesystemCacheLocalRetention = 5 * time.Minute
+
+func (c *filesystemCache) Get(key string) ([]string, error) {
+	c.mu.Lock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.mu.Unlock()
+		return nil, gotenberg.ErrConversionCacheMiss
+	}
+
+	entry := elem.Value.(*filesystemCacheEntry)
+
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.removeLocked(elem)
+		c.mu.Unlock()
+		return nil, gotenberg.ErrConversionCacheMiss
+	}
+
+	c.lru.MoveToFront(elem)
+	entryPaths := entry.paths
+	c.mu.Unlock()
+
+	// entryPaths lives under the shared cache directory, which a concurrent
+	// Put-triggered eviction or the cache admin route's Delete can
+	// os.RemoveAll out from under a caller still reading these files. Copy
+	// them out to a private directory first, same as s3Cache.Get does for
+	// its own downloads.
+	tmpDir, err := os.MkdirTemp("", "gotenberg-fs-cache-*")
+	if err != nil {
+		return nil, fmt.Errorf("create temporary directory: %w", err)
+	}
+
+	paths := make([]string, len(entryPaths))
+
+	for i, path := range entryPaths {
+		localPath := filepath.Join(tmpDir, filepath.Base(path))
+
+		_, err := copyFile(path, localPath)
+		if err != nil {
+			_ = os.RemoveAll(tmpDir)
+			return nil, fmt.Errorf("copy cached file '%s': %w", path, err)
+		}
+
+		paths[i] = localPath
+	}
+
+	time.AfterFunc(filesystemCacheLocalRetention, func() {
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	return paths, nil
+}
+
+func (c *filesystemCache) Put(key string, paths []string, ttl time.Duration) error {
+	entryDir := filepath.Join(c.dir, uuid.NewString())
+
+	err := os.MkdirAll(entryDir, 0o755)
+	if err != nil {
+		return fmt.Errorf("create cache entry directory: %w", err)
+	}
+
+	cachedPaths := make([]string, len(paths))
+	var size int64
+
+	for i, path := range paths {
+		cachedPath := filepath.Join(entryDir, filepath.Base(path))
+
+		n, err := copyFile(path, cachedPath)
+		if err != nil {
+			return fmt.Errorf("copy output to cache: %w", err)
+		}
+
+		cachedPaths[i] = cachedPath
+		size += n
+	}
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if old, ok := c.entries[key]; ok {
+		c.removeLocked(old)
+	}
+
+	entry := &filesystemCacheEntry{key: key, dir: entryDir, paths: cachedPaths, size: size, expiresAt: expiresAt}
+	c.entries[key] = c.lru.PushFront(entry)
+	c.currentSize += size
+
+	c.evictLocked()
+
+	return nil
+}
+
+func (c *filesystemCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+
+	c.removeLocked(elem)
+
+	return nil
+}
+
+// evictLocked removes entries from the back of the LRU list (the least
+// recently used ones) until the cache fits within maxSize. Callers must
+// hold c.mu.
+func (c *filesystemCache) evictLocked() {
+	for c.maxSize > 0 && c.currentSize > c.maxSize {
+		back := c.lru.Back()
+		if back == nil {
+			return
+		}
+
+		c.removeLocked(back)
+	}
+}
+
+// removeLocked evicts a single entry. Callers must hold c.mu.
+func (c *filesystemCache) removeLocked(elem *list.Element) {
+	entry := elem.Value.(*filesystemCacheEntry)
+
+	_ = os.RemoveAll(entry.dir)
+	c.currentSize -= entry.size
+	delete(c.entries, entry.key)
+	c.lru.Remove(elem)
+}
+
+func copyFile(src, dst string) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, fmt.Errorf("open source file: %w", err)
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return 0, fmt.Errorf("create destination file: %w", err)
+	}
+	defer func() {
+		_ = out.Close()
+	}()
+
+	return io.Copy(out, in)
+}
+
+var _ gotenberg.ConversionCache = (*filesystemCache)(nil)
@@ -0,0 +1,49 @@
+package conversioncache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFilesystemCacheGetSurvivesConcurrentEviction(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := newFilesystemCache(dir, 20)
+	if err != nil {
+		t.Fatalf("new filesystem cache: %v", err)
+	}
+
+	inputPath := filepath.Join(t.TempDir(), "output.pdf")
+	if err := os.WriteFile(inputPath, []byte("%PDF-1.4 test"), 0o644); err != nil {
+		t.Fatalf("write test file: %v", err)
+	}
+
+	if err := c.Put("key-a", []string{inputPath}, 0); err != nil {
+		t.Fatalf("put key-a: %v", err)
+	}
+
+	paths, err := c.Get("key-a")
+	if err != nil {
+		t.Fatalf("get key-a: %v", err)
+	}
+
+	// Put-ing a second entry evicts key-a's underlying directory (maxSize is
+	// 20 bytes, room for only one 13-byte entry), since that's the whole
+	// point of this test: paths returned by
+	// Get above must stay readable even though the entry backing them no
+	// longer exists in the cache.
+	if err := c.Put("key-b", []string{inputPath}, 0); err != nil {
+		t.Fatalf("put key-b: %v", err)
+	}
+
+	if _, err := c.Get("key-a"); err == nil {
+		t.Fatal("expected key-a to have been evicted")
+	}
+
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("expected %s returned by Get to still be readable after eviction: %v", path, err)
+		}
+	}
+}
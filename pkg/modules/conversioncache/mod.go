@@ -0,0 +1,100 @@
+// Package conversioncache provides the gotenberg.ConversionCache backends
+// (filesystem and S3-compatible) used to short-circuit conversions that
+// have already been performed for the same input and options.
+package conversioncache
+
+import (
+	"fmt"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(ConversionCache))
+}
+
+// ConversionCache is a module which selects and provisions the configured
+// gotenberg.ConversionCache backend.
+type ConversionCache struct {
+	backend string
+	ttl     time.Duration
+	maxSize int64
+	dir     string
+
+	s3Endpoint  string
+	s3Bucket    string
+	s3AccessKey string
+	s3SecretKey string
+
+	cache gotenberg.ConversionCache
+}
+
+// Descriptor returns a ConversionCache's module descriptor.
+func (mod *ConversionCache) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: func() string { return "conversion-cache" },
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("conversion-cache", flag.ExitOnError)
+			fs.String("libreoffice-cache-backend", "filesystem", "Backend used to cache conversions: filesystem or s3")
+			fs.Duration("libreoffice-cache-ttl", 24*time.Hour, "Time-to-live of a cached conversion artifact")
+			fs.Int64("libreoffice-cache-max-size", 1<<30, "Maximum size in bytes of the filesystem cache before the LRU eviction kicks in")
+			fs.String("libreoffice-cache-dir", "libreoffice-cache", "Directory used by the filesystem cache backend")
+			fs.String("libreoffice-cache-s3-endpoint", "", "Endpoint of the S3-compatible (MinIO) bucket used by the s3 cache backend")
+			fs.String("libreoffice-cache-s3-bucket", "", "Bucket used by the s3 cache backend")
+			fs.String("libreoffice-cache-s3-access-key", "", "Access key used by the s3 cache backend")
+			fs.String("libreoffice-cache-s3-secret-key", "", "Secret key used by the s3 cache backend")
+
+			return fs
+		},
+		New: func() gotenberg.Module { return new(ConversionCache) },
+	}
+}
+
+func (mod *ConversionCache) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+
+	mod.backend = flags.MustString("libreoffice-cache-backend")
+	mod.ttl = flags.MustDuration("libreoffice-cache-ttl")
+	mod.maxSize = flags.MustInt64("libreoffice-cache-max-size")
+	mod.dir = flags.MustString("libreoffice-cache-dir")
+	mod.s3Endpoint = flags.MustString("libreoffice-cache-s3-endpoint")
+	mod.s3Bucket = flags.MustString("libreoffice-cache-s3-bucket")
+	mod.s3AccessKey = flags.MustString("libreoffice-cache-s3-access-key")
+	mod.s3SecretKey = flags.MustString("libreoffice-cache-s3-secret-key")
+
+	switch mod.backend {
+	case "filesystem":
+		cache, err := newFilesystemCache(mod.dir, mod.maxSize)
+		if err != nil {
+			return fmt.Errorf("create filesystem conversion cache: %w", err)
+		}
+
+		mod.cache = cache
+	case "s3":
+		cache, err := newS3Cache(mod.s3Endpoint, mod.s3Bucket, mod.s3AccessKey, mod.s3SecretKey)
+		if err != nil {
+			return fmt.Errorf("create S3 conversion cache: %w", err)
+		}
+
+		mod.cache = cache
+	default:
+		return fmt.Errorf("unknown libreoffice-cache-backend '%s'", mod.backend)
+	}
+
+	return nil
+}
+
+// ConversionCache returns the provisioned gotenberg.ConversionCache backend.
+func (mod *ConversionCache) ConversionCache() (gotenberg.ConversionCache, error) {
+	return mod.cache, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module                  = (*ConversionCache)(nil)
+	_ gotenberg.Provisioner             = (*ConversionCache)(nil)
+	_ gotenberg.ConversionCacheProvider = (*ConversionCache)(nil)
+)
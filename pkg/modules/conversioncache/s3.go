@@ -0,0 +1,166 @@
+package conversioncache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+)
+
+// s3Cache is a gotenberg.ConversionCache backend storing artifacts in an
+// S3-compatible (MinIO) bucket, so that the cache can be shared across
+// Gotenberg replicas.
+type s3Cache struct {
+	client *minio.Client
+	bucket string
+}
+
+// s3CacheLocalRetention bounds how long the local copies Get downloads on
+// every cache hit are kept around before being swept, so that sustained
+// cache-hit traffic doesn't grow disk usage without bound.
+const s3CacheLocalRetention = 5 * time.Minute
+
+// s3CacheManifest lists the object keys making up a single cache entry, so
+// that Get can fetch them back as local files.
+type s3CacheManifest struct {
+	Objects   []string  `json:"objects"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func newS3Cache(endpoint, bucket, accessKey, secretKey string) (*s3Cache, error) {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds: credentials.NewStaticV4(accessKey, secretKey, ""),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create MinIO client: %w", err)
+	}
+
+	return &s3Cache{client: client, bucket: bucket}, nil
+}
+
+func (c *s3Cache) Get(key string) ([]string, error) {
+	ctx := context.Background()
+
+	manifest, err := c.getManifest(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !manifest.ExpiresAt.IsZero() && time.Now().After(manifest.ExpiresAt) {
+		_ = c.Delete(key)
+		return nil, gotenberg.ErrConversionCacheMiss
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gotenberg-s3-cache-*")
+	if err != nil {
+		return nil, fmt.Errorf("create temporary directory: %w", err)
+	}
+
+	paths := make([]string, len(manifest.Objects))
+
+	for i, object := range manifest.Objects {
+		localPath := filepath.Join(tmpDir, filepath.Base(object))
+
+		err = c.client.FGetObject(ctx, c.bucket, object, localPath, minio.GetObjectOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("download cached object '%s': %w", object, err)
+		}
+
+		paths[i] = localPath
+	}
+
+	time.AfterFunc(s3CacheLocalRetention, func() {
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	return paths, nil
+}
+
+func (c *s3Cache) Put(key string, paths []string, ttl time.Duration) error {
+	ctx := context.Background()
+
+	objects := make([]string, len(paths))
+
+	for i, path := range paths {
+		object := fmt.Sprintf("%s/%d-%s", key, i, filepath.Base(path))
+
+		_, err := c.client.FPutObject(ctx, c.bucket, object, path, minio.PutObjectOptions{})
+		if err != nil {
+			return fmt.Errorf("upload output to cache: %w", err)
+		}
+
+		objects[i] = object
+	}
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	manifest := s3CacheManifest{Objects: objects, ExpiresAt: expiresAt}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return fmt.Errorf("marshal cache manifest: %w", err)
+	}
+
+	_, err = c.client.PutObject(ctx, c.bucket, manifestKey(key), strings.NewReader(string(data)), int64(len(data)), minio.PutObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("upload cache manifest: %w", err)
+	}
+
+	return nil
+}
+
+func (c *s3Cache) Delete(key string) error {
+	ctx := context.Background()
+
+	manifest, err := c.getManifest(ctx, key)
+	if err != nil {
+		if err == gotenberg.ErrConversionCacheMiss {
+			return nil
+		}
+
+		return err
+	}
+
+	for _, object := range manifest.Objects {
+		_ = c.client.RemoveObject(ctx, c.bucket, object, minio.RemoveObjectOptions{})
+	}
+
+	return c.client.RemoveObject(ctx, c.bucket, manifestKey(key), minio.RemoveObjectOptions{})
+}
+
+func (c *s3Cache) getManifest(ctx context.Context, key string) (s3CacheManifest, error) {
+	obj, err := c.client.GetObject(ctx, c.bucket, manifestKey(key), minio.GetObjectOptions{})
+	if err != nil {
+		return s3CacheManifest{}, gotenberg.ErrConversionCacheMiss
+	}
+
+	defer func() {
+		_ = obj.Close()
+	}()
+
+	var manifest s3CacheManifest
+
+	err = json.NewDecoder(obj).Decode(&manifest)
+	if err != nil {
+		return s3CacheManifest{}, gotenberg.ErrConversionCacheMiss
+	}
+
+	return manifest, nil
+}
+
+func manifestKey(key string) string {
+	return fmt.Sprintf("%s/manifest.json", key)
+}
+
+var _ gotenberg.ConversionCache = (*s3Cache)(nil)
@@ -0,0 +1,27 @@
+package grpcapi
+
+import (
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// noopLogger gives the reused uno.API/gotenberg.PDFEngine calls a usable
+// logger until request-scoped logging is threaded through the gRPC
+// interceptors.
+func noopLogger() *zap.Logger {
+	return zap.NewNop()
+}
+
+// generateWorkdirPath mirrors api.Context.GeneratePath for the gRPC
+// transport, which has no HTTP request context to generate paths from.
+func generateWorkdirPath(workdir, extension string) string {
+	return filepath.Join(workdir, uuid.NewString()+extension)
+}
+
+// fileExtension returns the extension of name, defaulting to no extension
+// if name has none.
+func fileExtension(name string) string {
+	return filepath.Ext(name)
+}
@@ -0,0 +1,30 @@
+package grpcapi
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFileExtension(t *testing.T) {
+	if got := fileExtension("report.pdf"); got != ".pdf" {
+		t.Fatalf("expected '.pdf', got %q", got)
+	}
+
+	if got := fileExtension("no-extension"); got != "" {
+		t.Fatalf("expected no extension, got %q", got)
+	}
+}
+
+func TestGenerateWorkdirPathStaysUnderWorkdir(t *testing.T) {
+	workdir := t.TempDir()
+
+	path := generateWorkdirPath(workdir, ".png")
+
+	if filepath.Ext(path) != ".png" {
+		t.Fatalf("expected generated path to keep the requested extension, got %q", path)
+	}
+
+	if filepath.Dir(path) != workdir {
+		t.Fatalf("expected generated path to live under %q, got %q", workdir, path)
+	}
+}
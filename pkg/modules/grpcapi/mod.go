@@ -0,0 +1,105 @@
+// Package grpcapi exposes a gRPC transport alongside the HTTP multipart API
+// provided by pkg/modules/api. It reuses the same uno.API/gotenberg.PDFEngine
+// dependencies as the HTTP routes, and ConvertDocument/GenerateThumbnail call
+// the exact same conversion functions (uno.API.PDF, libreoffice.CreatePNG) as
+// their HTTP counterparts. Rasterize is the exception: like its HTTP
+// counterpart, it only works against a uno.API backend that additionally
+// implements image rendering, and returns codes.Unimplemented otherwise.
+//
+//go:generate protoc --go_out=. --go-grpc_out=. proto/gotenberg/v1/libreoffice.proto
+package grpcapi
+
+import (
+	"fmt"
+	"net"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v7/pkg/modules/grpcapi/gen/gotenberg/v1"
+	"github.com/gotenberg/gotenberg/v7/pkg/modules/libreoffice/uno"
+	"google.golang.org/grpc"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(GrpcAPI))
+}
+
+// GrpcAPI is a module which spins up a gRPC server reflecting the routes
+// exposed over HTTP by the other api-like modules.
+type GrpcAPI struct {
+	port     int
+	listener net.Listener
+	server   *grpc.Server
+}
+
+// Descriptor returns a GrpcAPI's module descriptor.
+func (mod *GrpcAPI) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: func() string { return "grpc-api" },
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("grpc-api", flag.ExitOnError)
+			fs.Int("grpc-port", 3000, "Port on which the gRPC server listens")
+
+			return fs
+		},
+		New: func() gotenberg.Module { return new(GrpcAPI) },
+	}
+}
+
+func (mod *GrpcAPI) Provision(ctx *gotenberg.Context) error {
+	mod.port = ctx.ParsedFlags().MustInt("grpc-port")
+
+	provider, err := ctx.Module(new(uno.Provider))
+	if err != nil {
+		return fmt.Errorf("get uno provider: %w", err)
+	}
+
+	unoAPI, err := provider.(uno.Provider).Uno()
+	if err != nil {
+		return fmt.Errorf("get uno API: %w", err)
+	}
+
+	engineProvider, err := ctx.Module(new(gotenberg.PDFEngineProvider))
+	if err != nil {
+		return fmt.Errorf("get PDF engine provider: %w", err)
+	}
+
+	engine, err := engineProvider.(gotenberg.PDFEngineProvider).PDFEngine()
+	if err != nil {
+		return fmt.Errorf("get PDF engine: %w", err)
+	}
+
+	mod.server = grpc.NewServer()
+	gotenbergv1.RegisterLibreOfficeServiceServer(mod.server, newLibreOfficeServer(unoAPI, engine))
+
+	return nil
+}
+
+// StartRoutes starts listening for gRPC connections on mod.port, mirroring
+// the lifecycle of the HTTP api module's Start.
+func (mod *GrpcAPI) StartRoutes() error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", mod.port))
+	if err != nil {
+		return fmt.Errorf("listen on gRPC port %d: %w", mod.port, err)
+	}
+
+	mod.listener = listener
+
+	go func() {
+		_ = mod.server.Serve(listener)
+	}()
+
+	return nil
+}
+
+func (mod *GrpcAPI) StopRoutes() error {
+	mod.server.GracefulStop()
+	return nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*GrpcAPI)(nil)
+	_ gotenberg.Provisioner = (*GrpcAPI)(nil)
+)
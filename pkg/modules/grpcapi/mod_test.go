@@ -0,0 +1,21 @@
+package grpcapi
+
+import "testing"
+
+// TestDescriptorRegistersGrpcPortFlag guards against mod.port silently
+// staying at its zero value: every sibling module configures its listen
+// port through a FlagSet, and GrpcAPI's Descriptor must do the same.
+func TestDescriptorRegistersGrpcPortFlag(t *testing.T) {
+	mod := new(GrpcAPI)
+
+	fs := mod.Descriptor().FlagSet()
+
+	port, err := fs.GetInt("grpc-port")
+	if err != nil {
+		t.Fatalf("expected a 'grpc-port' flag to be registered: %v", err)
+	}
+
+	if port != 3000 {
+		t.Fatalf("expected default grpc-port 3000, got %d", port)
+	}
+}
@@ -0,0 +1,302 @@
+package grpcapi
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+	gotenbergv1 "github.com/gotenberg/gotenberg/v7/pkg/modules/grpcapi/gen/gotenberg/v1"
+	"github.com/gotenberg/gotenberg/v7/pkg/modules/libreoffice"
+	"github.com/gotenberg/gotenberg/v7/pkg/modules/libreoffice/uno"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// libreOfficeServer implements gotenbergv1.LibreOfficeServiceServer by
+// reusing the same uno.API/gotenberg.PDFEngine dependencies as the HTTP
+// convertRoute and generateThumnailRoute.
+type libreOfficeServer struct {
+	gotenbergv1.UnimplementedLibreOfficeServiceServer
+
+	unoAPI uno.API
+	engine gotenberg.PDFEngine
+}
+
+func newLibreOfficeServer(unoAPI uno.API, engine gotenberg.PDFEngine) *libreOfficeServer {
+	return &libreOfficeServer{unoAPI: unoAPI, engine: engine}
+}
+
+// ConvertDocument receives the conversion options followed by one or
+// several file chunk streams, writes them to a temporary working
+// directory, converts each with uno.API.PDF and merges them if requested,
+// then streams the resulting PDF(s) back, chunk by chunk. Unlike
+// convertRoute, it does not apply the pdf_format and post_processors
+// options.
+func (s *libreOfficeServer) ConvertDocument(stream gotenbergv1.LibreOfficeService_ConvertDocumentServer) error {
+	workdir, err := os.MkdirTemp("", "gotenberg-grpc-convert-*")
+	if err != nil {
+		return fmt.Errorf("create working directory: %w", err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(workdir)
+	}()
+
+	options, inputPaths, err := receiveConvertRequest(stream, workdir)
+	if err != nil {
+		return fmt.Errorf("receive convert request: %w", err)
+	}
+
+	outputPaths := make([]string, len(inputPaths))
+
+	for i, inputPath := range inputPaths {
+		outputPaths[i] = generateWorkdirPath(workdir, ".pdf")
+
+		unoOptions := uno.Options{
+			Landscape:  options.Landscape,
+			PageRanges: options.NativePageRanges,
+			PDFformat:  options.NativePdfFormat,
+		}
+
+		err = s.unoAPI.PDF(stream.Context(), noopLogger(), inputPath, outputPaths[i], unoOptions)
+		if err != nil {
+			return fmt.Errorf("convert to PDF: %w", err)
+		}
+
+		err = stream.Send(&gotenbergv1.ConvertDocumentResponse{
+			Payload: &gotenbergv1.ConvertDocumentResponse_Progress{
+				Progress: &gotenbergv1.Progress{FileIndex: int32(i), Percent: 100},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("send progress: %w", err)
+		}
+	}
+
+	if options.Merge && len(outputPaths) > 1 {
+		mergedPath := generateWorkdirPath(workdir, ".pdf")
+
+		err = s.engine.Merge(stream.Context(), noopLogger(), outputPaths, mergedPath)
+		if err != nil {
+			return fmt.Errorf("merge PDFs: %w", err)
+		}
+
+		outputPaths = []string{mergedPath}
+	}
+
+	return sendFileChunks(stream, outputPaths)
+}
+
+// GenerateThumbnail mirrors generateThumnailRoute over streaming gRPC: one
+// PNG thumbnail (first page) per input file, generated by the same
+// libreoffice.CreatePNG call the HTTP route uses, so both transports go
+// through identical conversion code.
+func (s *libreOfficeServer) GenerateThumbnail(stream gotenbergv1.LibreOfficeService_GenerateThumbnailServer) error {
+	workdir, err := os.MkdirTemp("", "gotenberg-grpc-thumbnail-*")
+	if err != nil {
+		return fmt.Errorf("create working directory: %w", err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(workdir)
+	}()
+
+	options, inputPaths, err := receiveThumbnailRequest(stream, workdir)
+	if err != nil {
+		return fmt.Errorf("receive thumbnail request: %w", err)
+	}
+
+	outputPaths := make([]string, len(inputPaths))
+
+	for i, inputPath := range inputPaths {
+		outputPaths[i] = generateWorkdirPath(workdir, ".png")
+
+		unoOptions := uno.Options{
+			Landscape:  options.Landscape,
+			PageRanges: options.NativePageRanges,
+		}
+
+		err = libreoffice.CreatePNG(stream.Context(), noopLogger(), inputPath, outputPaths[i], unoOptions)
+		if err != nil {
+			return fmt.Errorf("create thumbnail: %w", err)
+		}
+
+		err = stream.Send(&gotenbergv1.GenerateThumbnailResponse{
+			Payload: &gotenbergv1.GenerateThumbnailResponse_Progress{
+				Progress: &gotenbergv1.Progress{FileIndex: int32(i), Percent: 100},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("send progress: %w", err)
+		}
+	}
+
+	return sendThumbnailChunks(stream, outputPaths)
+}
+
+// Rasterize mirrors rasterizeRoute over streaming gRPC: the rendered and
+// post-processed (crop/resize/re-encode) image of every input file, one
+// image per input file.
+func (s *libreOfficeServer) Rasterize(stream gotenbergv1.LibreOfficeService_RasterizeServer) error {
+	rasterAPI, ok := s.unoAPI.(imageRenderingAPI)
+	if !ok {
+		return status.Error(codes.Unimplemented, "the configured LibreOffice backend does not support rendering to raster images")
+	}
+
+	workdir, err := os.MkdirTemp("", "gotenberg-grpc-rasterize-*")
+	if err != nil {
+		return fmt.Errorf("create working directory: %w", err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(workdir)
+	}()
+
+	options, inputPaths, err := receiveRasterizeRequest(stream, workdir)
+	if err != nil {
+		return fmt.Errorf("receive rasterize request: %w", err)
+	}
+
+	extension, ok := rasterImageExtensions[options.Format]
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "unknown image format '%s'; expected png, jpeg or webp", options.Format)
+	}
+
+	var outputPaths []string
+
+	for _, inputPath := range inputPaths {
+		renderPath := generateWorkdirPath(workdir, extension)
+
+		unoOptions := uno.Options{
+			Landscape:  options.Landscape,
+			PageRanges: options.PageRanges,
+		}
+
+		err = rasterAPI.Image(noopLogger(), inputPath, renderPath, unoOptions)
+		if err != nil {
+			return fmt.Errorf("render page: %w", err)
+		}
+
+		processedPath := generateWorkdirPath(workdir, extension)
+
+		err = libreoffice.ProcessRasterImage(stream.Context(), noopLogger(), renderPath, processedPath, libreoffice.RasterOptions{
+			Format:  options.Format,
+			Quality: int(options.Quality),
+			Width:   int(options.Width),
+			Height:  int(options.Height),
+			Crop: libreoffice.CropRect{
+				X:      int(options.CropX),
+				Y:      int(options.CropY),
+				Width:  int(options.CropW),
+				Height: int(options.CropH),
+			},
+			Dpi: int(options.Dpi),
+		})
+		if err != nil {
+			return fmt.Errorf("process raster image: %w", err)
+		}
+
+		outputPaths = append(outputPaths, processedPath)
+	}
+
+	return sendRasterizeChunks(stream, outputPaths)
+}
+
+// receiveConvertRequest reads the leading ConvertOptions message followed by
+// one or several FileChunk streams, writing each file to workdir.
+func receiveConvertRequest(stream gotenbergv1.LibreOfficeService_ConvertDocumentServer, workdir string) (*gotenbergv1.ConvertOptions, []string, error) {
+	msg, err := stream.Recv()
+	if err != nil {
+		return nil, nil, fmt.Errorf("receive options: %w", err)
+	}
+
+	options, ok := msg.GetPayload().(*gotenbergv1.ConvertDocumentRequest_Options)
+	if !ok {
+		return nil, nil, fmt.Errorf("first message on the stream must carry ConvertOptions")
+	}
+
+	files := make(map[int32]*os.File)
+	var inputPaths []string
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("receive chunk: %w", err)
+		}
+
+		chunk, ok := msg.GetPayload().(*gotenbergv1.ConvertDocumentRequest_Chunk)
+		if !ok {
+			return nil, nil, fmt.Errorf("expected a FileChunk message")
+		}
+
+		f, ok := files[chunk.Chunk.FileIndex]
+		if !ok {
+			path := generateWorkdirPath(workdir, fileExtension(chunk.Chunk.FileName))
+
+			f, err = os.Create(path)
+			if err != nil {
+				return nil, nil, fmt.Errorf("create input file: %w", err)
+			}
+
+			files[chunk.Chunk.FileIndex] = f
+			inputPaths = append(inputPaths, path)
+		}
+
+		_, err = f.Write(chunk.Chunk.Data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("write chunk: %w", err)
+		}
+
+		if chunk.Chunk.Last {
+			_ = f.Close()
+		}
+	}
+
+	return options.Options, inputPaths, nil
+}
+
+// sendFileChunks streams each file at outputPaths back to the client as a
+// sequence of FileChunk messages.
+func sendFileChunks(stream gotenbergv1.LibreOfficeService_ConvertDocumentServer, outputPaths []string) error {
+	const chunkSize = 64 * 1024
+
+	for i, path := range outputPaths {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open output file: %w", err)
+		}
+
+		buf := make([]byte, chunkSize)
+
+		for {
+			n, err := f.Read(buf)
+			if n > 0 {
+				sendErr := stream.Send(&gotenbergv1.ConvertDocumentResponse{
+					Payload: &gotenbergv1.ConvertDocumentResponse_Chunk{
+						Chunk: &gotenbergv1.FileChunk{FileIndex: int32(i), Data: buf[:n], Last: err == io.EOF},
+					},
+				})
+				if sendErr != nil {
+					_ = f.Close()
+					return fmt.Errorf("send chunk: %w", sendErr)
+				}
+			}
+
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				_ = f.Close()
+				return fmt.Errorf("read output file: %w", err)
+			}
+		}
+
+		_ = f.Close()
+	}
+
+	return nil
+}
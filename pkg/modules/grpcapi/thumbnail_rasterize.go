@@ -0,0 +1,211 @@
+package grpcapi
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	gotenbergv1 "github.com/gotenberg/gotenberg/v7/pkg/modules/grpcapi/gen/gotenberg/v1"
+	"github.com/gotenberg/gotenberg/v7/pkg/modules/libreoffice/uno"
+	"go.uber.org/zap"
+)
+
+// imageRenderingAPI is satisfied by a uno.API implementation that is also
+// able to render a document's pages to raster images, mirroring the
+// libreoffice package's own rasterizerAPI capability interface. Rasterize
+// type-asserts against it instead of depending on a method that not every
+// uno.API implementation provides; GenerateThumbnail doesn't need it since
+// it reuses libreoffice.CreatePNG instead.
+type imageRenderingAPI interface {
+	uno.API
+	Image(logger *zap.Logger, inputPath, outputPath string, options uno.Options) error
+}
+
+// rasterImageExtensions mirrors the libreoffice package's rasterImageFormats
+// for the gRPC transport, which has no form field validation to rely on.
+var rasterImageExtensions = map[string]string{
+	"png":  ".png",
+	"jpeg": ".jpeg",
+	"webp": ".webp",
+}
+
+// receiveThumbnailRequest reads the leading ThumbnailOptions message
+// followed by one or several FileChunk streams, writing each file to
+// workdir.
+func receiveThumbnailRequest(stream gotenbergv1.LibreOfficeService_GenerateThumbnailServer, workdir string) (*gotenbergv1.ThumbnailOptions, []string, error) {
+	msg, err := stream.Recv()
+	if err != nil {
+		return nil, nil, fmt.Errorf("receive options: %w", err)
+	}
+
+	options, ok := msg.GetPayload().(*gotenbergv1.GenerateThumbnailRequest_Options)
+	if !ok {
+		return nil, nil, fmt.Errorf("first message on the stream must carry ThumbnailOptions")
+	}
+
+	inputPaths, err := receiveFileChunks(workdir, func() (fileChunkMsg, error) {
+		msg, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+
+		chunk, ok := msg.GetPayload().(*gotenbergv1.GenerateThumbnailRequest_Chunk)
+		if !ok {
+			return nil, fmt.Errorf("expected a FileChunk message")
+		}
+
+		return chunk.Chunk, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return options.Options, inputPaths, nil
+}
+
+// receiveRasterizeRequest reads the leading RasterizeOptions message
+// followed by one or several FileChunk streams, writing each file to
+// workdir.
+func receiveRasterizeRequest(stream gotenbergv1.LibreOfficeService_RasterizeServer, workdir string) (*gotenbergv1.RasterizeOptions, []string, error) {
+	msg, err := stream.Recv()
+	if err != nil {
+		return nil, nil, fmt.Errorf("receive options: %w", err)
+	}
+
+	options, ok := msg.GetPayload().(*gotenbergv1.RasterizeRequest_Options)
+	if !ok {
+		return nil, nil, fmt.Errorf("first message on the stream must carry RasterizeOptions")
+	}
+
+	inputPaths, err := receiveFileChunks(workdir, func() (fileChunkMsg, error) {
+		msg, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+
+		chunk, ok := msg.GetPayload().(*gotenbergv1.RasterizeRequest_Chunk)
+		if !ok {
+			return nil, fmt.Errorf("expected a FileChunk message")
+		}
+
+		return chunk.Chunk, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return options.Options, inputPaths, nil
+}
+
+// fileChunkMsg is the common shape of every oneof's FileChunk variant,
+// letting receiveFileChunks stay generic over ConvertDocument,
+// GenerateThumbnail and Rasterize.
+type fileChunkMsg = *gotenbergv1.FileChunk
+
+// receiveFileChunks drains a stream of FileChunk messages (obtained via
+// recv) into files under workdir, one per distinct FileIndex, and returns
+// their paths in first-seen order. It mirrors receiveConvertRequest's inner
+// loop so ConvertDocument, GenerateThumbnail and Rasterize all stage their
+// inputs the same way despite carrying different oneof message types.
+func receiveFileChunks(workdir string, recv func() (fileChunkMsg, error)) ([]string, error) {
+	files := make(map[int32]*os.File)
+	var inputPaths []string
+
+	for {
+		chunk, err := recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("receive chunk: %w", err)
+		}
+
+		f, ok := files[chunk.FileIndex]
+		if !ok {
+			path := generateWorkdirPath(workdir, fileExtension(chunk.FileName))
+
+			f, err = os.Create(path)
+			if err != nil {
+				return nil, fmt.Errorf("create input file: %w", err)
+			}
+
+			files[chunk.FileIndex] = f
+			inputPaths = append(inputPaths, path)
+		}
+
+		_, err = f.Write(chunk.Data)
+		if err != nil {
+			return nil, fmt.Errorf("write chunk: %w", err)
+		}
+
+		if chunk.Last {
+			_ = f.Close()
+		}
+	}
+
+	return inputPaths, nil
+}
+
+// sendThumbnailChunks streams each file at outputPaths back to the client as
+// a sequence of FileChunk messages.
+func sendThumbnailChunks(stream gotenbergv1.LibreOfficeService_GenerateThumbnailServer, outputPaths []string) error {
+	return sendFileChunksGeneric(outputPaths, func(index int32, data []byte, last bool) error {
+		return stream.Send(&gotenbergv1.GenerateThumbnailResponse{
+			Payload: &gotenbergv1.GenerateThumbnailResponse_Chunk{
+				Chunk: &gotenbergv1.FileChunk{FileIndex: index, Data: data, Last: last},
+			},
+		})
+	})
+}
+
+// sendRasterizeChunks streams each file at outputPaths back to the client as
+// a sequence of FileChunk messages.
+func sendRasterizeChunks(stream gotenbergv1.LibreOfficeService_RasterizeServer, outputPaths []string) error {
+	return sendFileChunksGeneric(outputPaths, func(index int32, data []byte, last bool) error {
+		return stream.Send(&gotenbergv1.RasterizeResponse{
+			Payload: &gotenbergv1.RasterizeResponse_Chunk{
+				Chunk: &gotenbergv1.FileChunk{FileIndex: index, Data: data, Last: last},
+			},
+		})
+	})
+}
+
+// sendFileChunksGeneric reads each file at paths and hands every chunk to
+// send, factoring out the read loop shared by sendThumbnailChunks and
+// sendRasterizeChunks (their response message types differ, so they can't
+// share a single Send call).
+func sendFileChunksGeneric(paths []string, send func(index int32, data []byte, last bool) error) error {
+	const chunkSize = 64 * 1024
+
+	for i, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open output file: %w", err)
+		}
+
+		buf := make([]byte, chunkSize)
+
+		for {
+			n, readErr := f.Read(buf)
+			if n > 0 {
+				sendErr := send(int32(i), buf[:n], readErr == io.EOF)
+				if sendErr != nil {
+					_ = f.Close()
+					return fmt.Errorf("send chunk: %w", sendErr)
+				}
+			}
+
+			if readErr == io.EOF {
+				break
+			}
+			if readErr != nil {
+				_ = f.Close()
+				return fmt.Errorf("read output file: %w", readErr)
+			}
+		}
+
+		_ = f.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package jobstore
+
+import (
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+)
+
+var jobsBucket = []byte("jobs")
+
+// boltStore persists jobs in a local BoltDB file: it survives a restart
+// without requiring an external dependency like Redis.
+type boltStore struct {
+	db *bolt.DB
+}
+
+func newBoltStore(path string) (*boltStore, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("open BoltDB file: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(jobsBucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create jobs bucket: %w", err)
+	}
+
+	return &boltStore{db: db}, nil
+}
+
+func (s *boltStore) Save(job gotenberg.Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("marshal job: %w", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Put([]byte(job.ID), data)
+	})
+}
+
+func (s *boltStore) Get(id string) (gotenberg.Job, error) {
+	var job gotenberg.Job
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(jobsBucket).Get([]byte(id))
+		if data == nil {
+			return gotenberg.ErrJobNotFound
+		}
+
+		return json.Unmarshal(data, &job)
+	})
+	if err != nil {
+		return gotenberg.Job{}, err
+	}
+
+	return job, nil
+}
+
+func (s *boltStore) Delete(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Delete([]byte(id))
+	})
+}
+
+var _ gotenberg.JobStore = (*boltStore)(nil)
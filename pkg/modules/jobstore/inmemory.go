@@ -0,0 +1,50 @@
+package jobstore
+
+import (
+	"sync"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+)
+
+// inMemoryStore is the default gotenberg.JobStore: fast, but jobs do not
+// survive a restart.
+type inMemoryStore struct {
+	mu   sync.RWMutex
+	jobs map[string]gotenberg.Job
+}
+
+func newInMemoryStore() *inMemoryStore {
+	return &inMemoryStore{jobs: make(map[string]gotenberg.Job)}
+}
+
+func (s *inMemoryStore) Save(job gotenberg.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[job.ID] = job
+
+	return nil
+}
+
+func (s *inMemoryStore) Get(id string) (gotenberg.Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return gotenberg.Job{}, gotenberg.ErrJobNotFound
+	}
+
+	return job, nil
+}
+
+func (s *inMemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+
+	return nil
+}
+
+var _ gotenberg.JobStore = (*inMemoryStore)(nil)
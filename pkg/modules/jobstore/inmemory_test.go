@@ -0,0 +1,54 @@
+package jobstore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+)
+
+func TestInMemoryStoreSaveGet(t *testing.T) {
+	store := newInMemoryStore()
+
+	job := gotenberg.Job{ID: "job-1", Status: gotenberg.JobStatusPending}
+
+	if err := store.Save(job); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	got, err := store.Get("job-1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if got.Status != gotenberg.JobStatusPending {
+		t.Fatalf("expected status %q, got %q", gotenberg.JobStatusPending, got.Status)
+	}
+}
+
+func TestInMemoryStoreGetMissing(t *testing.T) {
+	store := newInMemoryStore()
+
+	_, err := store.Get("does-not-exist")
+	if !errors.Is(err, gotenberg.ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound, got %v", err)
+	}
+}
+
+func TestInMemoryStoreDelete(t *testing.T) {
+	store := newInMemoryStore()
+
+	job := gotenberg.Job{ID: "job-1", Status: gotenberg.JobStatusDone}
+	if err := store.Save(job); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	if err := store.Delete("job-1"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	_, err := store.Get("job-1")
+	if !errors.Is(err, gotenberg.ErrJobNotFound) {
+		t.Fatalf("expected ErrJobNotFound after delete, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+// Package jobstore provides the gotenberg.JobStore backends (in-memory,
+// Redis and BoltDB) used by routes that support async job submission, such
+// as the LibreOffice convert and generate-thumbnail routes.
+package jobstore
+
+import (
+	"fmt"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(JobStore))
+}
+
+// JobStore is a module which selects and provisions the configured
+// gotenberg.JobStore backend.
+type JobStore struct {
+	backend string
+	redisURI string
+	boltPath string
+
+	store gotenberg.JobStore
+}
+
+// Descriptor returns a JobStore's module descriptor.
+func (mod *JobStore) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: func() string { return "job-store" },
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("job-store", flag.ExitOnError)
+			fs.String("job-store-backend", "memory", "Backend used to persist async jobs: memory, redis or bolt")
+			fs.String("job-store-redis-uri", "", "URI of the Redis instance used when job-store-backend is redis")
+			fs.String("job-store-bolt-path", "jobs.db", "Path to the BoltDB file used when job-store-backend is bolt")
+
+			return fs
+		},
+		New: func() gotenberg.Module { return new(JobStore) },
+	}
+}
+
+func (mod *JobStore) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+
+	mod.backend = flags.MustString("job-store-backend")
+	mod.redisURI = flags.MustString("job-store-redis-uri")
+	mod.boltPath = flags.MustString("job-store-bolt-path")
+
+	switch mod.backend {
+	case "memory":
+		mod.store = newInMemoryStore()
+	case "redis":
+		store, err := newRedisStore(mod.redisURI)
+		if err != nil {
+			return fmt.Errorf("create Redis job store: %w", err)
+		}
+
+		mod.store = store
+	case "bolt":
+		store, err := newBoltStore(mod.boltPath)
+		if err != nil {
+			return fmt.Errorf("create BoltDB job store: %w", err)
+		}
+
+		mod.store = store
+	default:
+		return fmt.Errorf("unknown job-store-backend '%s'", mod.backend)
+	}
+
+	return nil
+}
+
+// JobStore returns the provisioned gotenberg.JobStore backend.
+func (mod *JobStore) JobStore() (gotenberg.JobStore, error) {
+	return mod.store, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module             = (*JobStore)(nil)
+	_ gotenberg.Provisioner        = (*JobStore)(nil)
+	_ gotenberg.JobStoreProvider   = (*JobStore)(nil)
+)
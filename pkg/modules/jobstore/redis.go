@@ -0,0 +1,64 @@
+package jobstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+)
+
+// redisStore persists jobs in Redis so that they survive a Gotenberg
+// restart and can be shared across replicas.
+type redisStore struct {
+	client *redis.Client
+}
+
+func newRedisStore(uri string) (*redisStore, error) {
+	opts, err := redis.ParseURL(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parse Redis URI: %w", err)
+	}
+
+	return &redisStore{client: redis.NewClient(opts)}, nil
+}
+
+func (s *redisStore) Save(job gotenberg.Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("marshal job: %w", err)
+	}
+
+	return s.client.Set(context.Background(), jobKey(job.ID), data, 0).Err()
+}
+
+func (s *redisStore) Get(id string) (gotenberg.Job, error) {
+	data, err := s.client.Get(context.Background(), jobKey(id)).Bytes()
+	if err == redis.Nil {
+		return gotenberg.Job{}, gotenberg.ErrJobNotFound
+	}
+	if err != nil {
+		return gotenberg.Job{}, fmt.Errorf("get job: %w", err)
+	}
+
+	var job gotenberg.Job
+
+	err = json.Unmarshal(data, &job)
+	if err != nil {
+		return gotenberg.Job{}, fmt.Errorf("unmarshal job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (s *redisStore) Delete(id string) error {
+	return s.client.Del(context.Background(), jobKey(id)).Err()
+}
+
+func jobKey(id string) string {
+	return fmt.Sprintf("gotenberg:job:%s", id)
+}
+
+var _ gotenberg.JobStore = (*redisStore)(nil)
@@ -0,0 +1,411 @@
+package libreoffice
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v7/pkg/modules/api"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// asyncRequest captures the client-provided options for an async
+// conversion: the Prefer header / async form field, the tenant identifier
+// used for the concurrency cap, and the webhooks to call back on success or
+// failure.
+type asyncRequest struct {
+	enabled         bool
+	tenantID        string
+	webhookURL      string
+	webhookErrorURL string
+	webhookSecret   string
+}
+
+// parseAsyncRequest reads the async-related form fields/headers shared by
+// the convert and generate-thumbnail routes.
+func parseAsyncRequest(c echo.Context, ctx *api.Context) (asyncRequest, error) {
+	var (
+		async           bool
+		webhookURL      string
+		webhookErrorURL string
+		webhookSecret   string
+	)
+
+	err := ctx.FormData().
+		Bool("async", &async, false).
+		String("webhookUrl", &webhookURL, "").
+		String("webhookErrorUrl", &webhookErrorURL, "").
+		String("webhookSecret", &webhookSecret, "").
+		Validate()
+	if err != nil {
+		return asyncRequest{}, fmt.Errorf("validate async form data: %w", err)
+	}
+
+	if c.Request().Header.Get("Prefer") == "respond-async" {
+		async = true
+	}
+
+	return asyncRequest{
+		enabled:         async,
+		tenantID:        c.Request().Header.Get("Gotenberg-Tenant"),
+		webhookURL:      webhookURL,
+		webhookErrorURL: webhookErrorURL,
+		webhookSecret:   webhookSecret,
+	}, nil
+}
+
+// maxTrackedTenants bounds how many distinct tenantConcurrencyLimiter.tenants
+// entries are kept at once. tenantID comes straight from the unauthenticated,
+// client-controlled Gotenberg-Tenant header, so without a cap a caller that
+// varies it on every request could grow the map without bound; idle tenants
+// (no in-flight jobs) are evicted least-recently-used first once the cap is
+// reached. A var rather than a const so tests can exercise eviction without
+// creating thousands of tenants.
+var maxTrackedTenants = 10_000
+
+// tenantConcurrencyLimiter caps how many async jobs a given tenant may run
+// at once, so that a burst of requests from one tenant cannot starve the
+// shared mod.listener lock used by CreatePNG for everyone else.
+type tenantConcurrencyLimiter struct {
+	maxPerTenant int
+
+	mu      sync.Mutex
+	tenants map[string]*list.Element
+	lru     *list.List
+}
+
+// tenantSemaphore pairs a tenant's semaphore with its own LRU bookkeeping.
+type tenantSemaphore struct {
+	tenantID string
+	sem      chan struct{}
+}
+
+func newTenantConcurrencyLimiter(maxPerTenant int) *tenantConcurrencyLimiter {
+	return &tenantConcurrencyLimiter{
+		maxPerTenant: maxPerTenant,
+		tenants:      make(map[string]*list.Element),
+		lru:          list.New(),
+	}
+}
+
+// semaphore returns (creating it if necessary) the channel-based semaphore
+// for tenantID. l.mu only guards the creation/lookup/LRU bookkeeping in
+// l.tenants; the semaphore itself is then acquired/released lock-free.
+func (l *tenantConcurrencyLimiter) semaphore(tenantID string) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elem, ok := l.tenants[tenantID]; ok {
+		l.lru.MoveToFront(elem)
+		return elem.Value.(*tenantSemaphore).sem
+	}
+
+	if l.lru.Len() >= maxTrackedTenants {
+		l.evictIdleLocked()
+	}
+
+	sem := make(chan struct{}, l.maxPerTenant)
+	elem := l.lru.PushFront(&tenantSemaphore{tenantID: tenantID, sem: sem})
+	l.tenants[tenantID] = elem
+
+	return sem
+}
+
+// evictIdleLocked drops the least-recently-used tenant that currently has no
+// in-flight jobs, so the map never grows past maxTrackedTenants. Callers
+// must hold l.mu. It's a no-op if every tracked tenant is currently busy
+// (the map is then allowed to exceed the cap until one frees up).
+func (l *tenantConcurrencyLimiter) evictIdleLocked() {
+	for elem := l.lru.Back(); elem != nil; elem = elem.Prev() {
+		ts := elem.Value.(*tenantSemaphore)
+		if len(ts.sem) == 0 {
+			l.lru.Remove(elem)
+			delete(l.tenants, ts.tenantID)
+			return
+		}
+	}
+}
+
+func (l *tenantConcurrencyLimiter) acquire(ctx context.Context, tenantID string) error {
+	sem := l.semaphore(tenantID)
+
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *tenantConcurrencyLimiter) release(tenantID string) {
+	<-l.semaphore(tenantID)
+}
+
+// jobWorkspaceRoot is the base directory under which each async job gets
+// its own durable, request-independent workspace (see newJobWorkspace).
+var jobWorkspaceRoot = filepath.Join(os.TempDir(), "gotenberg-jobs")
+
+// jobArtifactRetention bounds how long a finished job's workspace is kept
+// around after completion, so that GET /jobs/{id}/result has time to be
+// called while not growing disk usage unbounded for jobs nobody collects.
+const jobArtifactRetention = 24 * time.Hour
+
+// newJobWorkspace creates the durable directory a single async job stages
+// its input and output files in. Unlike the submitting request's
+// api.Context working directory, this directory is not cleaned up once the
+// HTTP handler returns: it survives for jobArtifactRetention so that the
+// background goroutine can keep writing to it, and so that
+// GET /jobs/{id}/result can still find its files long after the original
+// request completed.
+func newJobWorkspace(jobID string) (string, error) {
+	dir := filepath.Join(jobWorkspaceRoot, jobID)
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return "", fmt.Errorf("create job workspace directory: %w", err)
+	}
+
+	return dir, nil
+}
+
+// scheduleWorkspaceCleanup removes dir after jobArtifactRetention has
+// elapsed.
+func scheduleWorkspaceCleanup(dir string) {
+	time.AfterFunc(jobArtifactRetention, func() {
+		_ = os.RemoveAll(dir)
+	})
+}
+
+// jobPathGenerator mirrors api.Context.GeneratePath for a job's durable
+// workspace: each call returns a fresh path under dir, suffixed with
+// extension.
+func jobPathGenerator(dir string) func(extension string) string {
+	return func(extension string) string {
+		return filepath.Join(dir, uuid.NewString()+extension)
+	}
+}
+
+// stageJobInputs copies inputPaths into dir so that the background
+// goroutine spawned by submitAsyncJob no longer depends on files living
+// under the submitting request's api.Context working directory, which is
+// cleaned up as soon as the handler returns the 202 Accepted response.
+func stageJobInputs(inputPaths []string, dir string) ([]string, error) {
+	staged := make([]string, len(inputPaths))
+
+	for i, inputPath := range inputPaths {
+		stagedPath := filepath.Join(dir, fmt.Sprintf("input-%d%s", i, filepath.Ext(inputPath)))
+
+		err := copyFile(inputPath, stagedPath)
+		if err != nil {
+			return nil, fmt.Errorf("stage job input '%s': %w", inputPath, err)
+		}
+
+		staged[i] = stagedPath
+	}
+
+	return staged, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open source file: %w", err)
+	}
+
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("create destination file: %w", err)
+	}
+
+	defer func() {
+		_ = out.Close()
+	}()
+
+	_, err = io.Copy(out, in)
+
+	return err
+}
+
+// submitAsyncJob persists a pending gotenberg.Job, replies 202 Accepted with
+// its ID, then runs work in the background against a durable, per-job
+// workspace directory (rather than the submitting request's transient
+// api.Context), updating the job and calling the configured webhook(s) once
+// it completes.
+func submitAsyncJob(
+	c echo.Context,
+	ctx *api.Context,
+	store gotenberg.JobStore,
+	limiter *tenantConcurrencyLimiter,
+	req asyncRequest,
+	inputPaths []string,
+	work func(workCtx context.Context, generatePath func(string) string, logger *zap.Logger, workInputPaths []string) ([]string, error),
+) error {
+	job := gotenberg.Job{
+		ID:         uuid.NewString(),
+		TenantID:   req.tenantID,
+		Status:     gotenberg.JobStatusPending,
+		WebhookURL: req.webhookURL,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	workspaceDir, err := newJobWorkspace(job.ID)
+	if err != nil {
+		return fmt.Errorf("create job workspace: %w", err)
+	}
+
+	// Input files are staged synchronously, while the submitting request's
+	// api.Context (and the uploaded files it owns) is still alive, so the
+	// background goroutine below never touches it.
+	workInputPaths, err := stageJobInputs(inputPaths, workspaceDir)
+	if err != nil {
+		_ = os.RemoveAll(workspaceDir)
+		return fmt.Errorf("stage job inputs: %w", err)
+	}
+
+	err = store.Save(job)
+	if err != nil {
+		_ = os.RemoveAll(workspaceDir)
+		return fmt.Errorf("save pending job: %w", err)
+	}
+
+	logger := ctx.Log()
+
+	go func() {
+		// The submitting request has already received its 202 response by
+		// the time this runs (or will shortly); context.Background() is
+		// used instead of ctx so that the work is not canceled once the
+		// HTTP request/response cycle ends.
+		bgCtx := context.Background()
+
+		err := limiter.acquire(bgCtx, req.tenantID)
+		if err != nil {
+			logger.Error(fmt.Sprintf("acquire tenant concurrency slot for job %s: %v", job.ID, err))
+			_ = os.RemoveAll(workspaceDir)
+
+			return
+		}
+		defer limiter.release(req.tenantID)
+
+		job.Status = gotenberg.JobStatusRunning
+		job.UpdatedAt = time.Now()
+		_ = store.Save(job)
+
+		resultPaths, err := work(bgCtx, jobPathGenerator(workspaceDir), logger, workInputPaths)
+
+		job.UpdatedAt = time.Now()
+
+		if err != nil {
+			job.Status = gotenberg.JobStatusFailed
+			job.Error = err.Error()
+			_ = store.Save(job)
+			_ = os.RemoveAll(workspaceDir)
+			deliverWebhook(logger, req.webhookErrorURL, req.webhookSecret, job)
+
+			return
+		}
+
+		job.Status = gotenberg.JobStatusDone
+		job.ResultPaths = resultPaths
+		_ = store.Save(job)
+		scheduleWorkspaceCleanup(workspaceDir)
+		deliverWebhook(logger, req.webhookURL, req.webhookSecret, job)
+	}()
+
+	return c.JSON(http.StatusAccepted, map[string]string{"jobId": job.ID})
+}
+
+// webhookRetrySchedule is the backoff applied between webhook delivery
+// attempts.
+var webhookRetrySchedule = []time.Duration{0, 2 * time.Second, 10 * time.Second, 30 * time.Second}
+
+// webhookPayload is the JSON body delivered to a job's webhook URL.
+type webhookPayload struct {
+	JobID       string   `json:"jobId"`
+	Status      string   `json:"status"`
+	ResultPaths []string `json:"resultPaths,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// deliverWebhook POSTs the job status to targetURL, signing the payload
+// with webhookSecret (if any) via an HMAC-SHA256 signature carried in the
+// Gotenberg-Signature header, retrying with backoff on failure.
+func deliverWebhook(logger *zap.Logger, targetURL, secret string, job gotenberg.Job) {
+	if targetURL == "" {
+		return
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		JobID:       job.ID,
+		Status:      string(job.Status),
+		ResultPaths: job.ResultPaths,
+		Error:       job.Error,
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("marshal webhook payload for job %s: %v", job.ID, err))
+		return
+	}
+
+	for attempt, wait := range webhookRetrySchedule {
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+
+		err := postWebhook(targetURL, secret, payload)
+		if err == nil {
+			return
+		}
+
+		logger.Warn(fmt.Sprintf("deliver webhook for job %s (attempt %d): %v", job.ID, attempt+1, err))
+	}
+
+	logger.Error(fmt.Sprintf("giving up delivering webhook for job %s after %d attempts", job.ID, len(webhookRetrySchedule)))
+}
+
+func postWebhook(targetURL, secret string, payload []byte) error {
+	req, err := http.NewRequest(http.MethodPost, targetURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(payload)
+		req.Header.Set("Gotenberg-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send webhook request: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package libreoffice
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+)
+
+// TestTenantConcurrencyLimiterConcurrentNewTenants exercises the race the
+// -race detector used to catch: many goroutines acquiring the semaphore for
+// brand new tenant IDs at once must never trigger a concurrent map write.
+func TestTenantConcurrencyLimiterConcurrentNewTenants(t *testing.T) {
+	limiter := newTenantConcurrencyLimiter(1)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		tenantID := string(rune('a' + i%26))
+
+		wg.Add(1)
+
+		go func(tenantID string) {
+			defer wg.Done()
+
+			err := limiter.acquire(context.Background(), tenantID)
+			if err != nil {
+				t.Errorf("acquire: %v", err)
+				return
+			}
+
+			limiter.release(tenantID)
+		}(tenantID)
+	}
+
+	wg.Wait()
+}
+
+// TestTenantConcurrencyLimiterCapsPerTenant asserts that a tenant cannot
+// exceed its configured number of concurrent slots.
+func TestTenantConcurrencyLimiterCapsPerTenant(t *testing.T) {
+	limiter := newTenantConcurrencyLimiter(2)
+
+	ctx := context.Background()
+
+	if err := limiter.acquire(ctx, "tenant-a"); err != nil {
+		t.Fatalf("acquire 1: %v", err)
+	}
+	if err := limiter.acquire(ctx, "tenant-a"); err != nil {
+		t.Fatalf("acquire 2: %v", err)
+	}
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	if err := limiter.acquire(cancelCtx, "tenant-a"); err == nil {
+		t.Fatal("expected acquire to fail once the context is canceled and no slot is free")
+	}
+
+	limiter.release("tenant-a")
+	limiter.release("tenant-a")
+}
+
+// TestTenantConcurrencyLimiterEvictsIdleTenants asserts that once
+// maxTrackedTenants is reached, an idle tenant (no in-flight jobs) is
+// evicted to make room for a new one, so the map doesn't grow without
+// bound as a client varies the Gotenberg-Tenant header.
+func TestTenantConcurrencyLimiterEvictsIdleTenants(t *testing.T) {
+	old := maxTrackedTenants
+	maxTrackedTenants = 2
+	defer func() { maxTrackedTenants = old }()
+
+	limiter := newTenantConcurrencyLimiter(1)
+	ctx := context.Background()
+
+	if err := limiter.acquire(ctx, "tenant-a"); err != nil {
+		t.Fatalf("acquire tenant-a: %v", err)
+	}
+	limiter.release("tenant-a")
+
+	if err := limiter.acquire(ctx, "tenant-b"); err != nil {
+		t.Fatalf("acquire tenant-b: %v", err)
+	}
+	limiter.release("tenant-b")
+
+	if err := limiter.acquire(ctx, "tenant-c"); err != nil {
+		t.Fatalf("acquire tenant-c: %v", err)
+	}
+	limiter.release("tenant-c")
+
+	limiter.mu.Lock()
+	n := len(limiter.tenants)
+	_, stillTracked := limiter.tenants["tenant-a"]
+	limiter.mu.Unlock()
+
+	if n > 2 {
+		t.Fatalf("expected at most 2 tracked tenants, got %d", n)
+	}
+	if stillTracked {
+		t.Fatal("expected tenant-a (least recently used, idle) to have been evicted")
+	}
+}
+
+// TestDeliverWebhookPayloadIsValidJSON checks that webhookPayload, the type
+// deliverWebhook marshals into the request body, encodes several result
+// paths as JSON that round-trips (unlike fmt.Sprintf("%q", ...) on a
+// []string, which renders `["/a.pdf" "/b.pdf"]`). It does not call
+// deliverWebhook itself.
+func TestDeliverWebhookPayloadIsValidJSON(t *testing.T) {
+	job := gotenberg.Job{
+		ID:          "job-1",
+		Status:      gotenberg.JobStatusDone,
+		ResultPaths: []string{"/tmp/a.pdf", "/tmp/b.pdf"},
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		JobID:       job.ID,
+		Status:      string(job.Status),
+		ResultPaths: job.ResultPaths,
+	})
+	if err != nil {
+		t.Fatalf("marshal webhook payload: %v", err)
+	}
+
+	var decoded webhookPayload
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+
+	if len(decoded.ResultPaths) != 2 || decoded.ResultPaths[1] != "/tmp/b.pdf" {
+		t.Fatalf("expected both result paths to round-trip, got %v", decoded.ResultPaths)
+	}
+}
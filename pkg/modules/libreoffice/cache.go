@@ -0,0 +1,192 @@
+package libreoffice
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v7/pkg/modules/api"
+	"github.com/labstack/echo/v4"
+)
+
+// cacheKeyOptions is the canonicalized, JSON-encoded form of the request
+// options hashed alongside the input bytes to derive a conversion cache
+// key. Field order is fixed by the struct so that encoding/json always
+// produces the same bytes for the same options.
+type cacheKeyOptions struct {
+	Landscape           bool              `json:"landscape"`
+	NativePageRanges    string            `json:"nativePageRanges"`
+	NativePDFformat     string            `json:"nativePdfFormat"`
+	PDFformat           string            `json:"pdfFormat"`
+	Merge               bool              `json:"merge"`
+	PostProcessors      []string          `json:"postProcessors"`
+	PostProcessorParams map[string]string `json:"postProcessorParams"`
+	Template            string            `json:"template"`
+	TemplateContentHash string            `json:"templateContentHash"`
+	LibreOfficeVersion  string            `json:"libreOfficeVersion"`
+	UnoconvVersion      string            `json:"unoconvVersion"`
+}
+
+// relevantPostProcessorParams merges, for every requested post-processing
+// step, only the form fields that step actually reads (see
+// postProcessorParamKeys), so that e.g. two "postProcessors=encrypt"
+// requests with different passwords never collide on the same cache key,
+// while an unrelated field left over from the form (say, a stale
+// watermarkText with no "watermark" step requested) doesn't cause
+// unnecessary cache misses.
+func relevantPostProcessorParams(names []string, params map[string]string) map[string]string {
+	relevant := make(map[string]string)
+
+	for _, name := range names {
+		for key, value := range stepParams(name, params) {
+			relevant[key] = value
+		}
+	}
+
+	return relevant
+}
+
+var (
+	libreOfficeVersionOnce sync.Once
+	libreOfficeVersionStr  string
+
+	unoconvVersionOnce sync.Once
+	unoconvVersionStr  string
+)
+
+// libreOfficeVersion returns the version string reported by the LibreOffice
+// binary available on PATH, resolved and cached once per process, so that
+// upgrading the binary automatically busts stale conversion cache entries.
+// It resolves to "" if no LibreOffice binary can be found or probed, in
+// which case the cache key simply stops discriminating by version.
+func libreOfficeVersion() string {
+	libreOfficeVersionOnce.Do(func() {
+		libreOfficeVersionStr = probeBinaryVersion("soffice", "libreoffice")
+	})
+
+	return libreOfficeVersionStr
+}
+
+// unoconvVersion mirrors libreOfficeVersion for the unoconv binary.
+func unoconvVersion() string {
+	unoconvVersionOnce.Do(func() {
+		unoconvVersionStr = probeBinaryVersion("unoconv")
+	})
+
+	return unoconvVersionStr
+}
+
+// probeBinaryVersion runs "<name> --version" for the first of names found
+// on PATH and returns its trimmed output, or "" if none of names is
+// available or the probe fails.
+func probeBinaryVersion(names ...string) string {
+	for _, name := range names {
+		binPath, err := exec.LookPath(name)
+		if err != nil {
+			continue
+		}
+
+		out, err := exec.Command(binPath, "--version").Output()
+		if err != nil {
+			continue
+		}
+
+		return strings.TrimSpace(string(out))
+	}
+
+	return ""
+}
+
+// computeCacheKey derives a stable cache key from the content of
+// inputPaths and the request options, so that an upgrade of the
+// LibreOffice/unoconv binaries automatically invalidates stale entries.
+func computeCacheKey(inputPaths []string, options cacheKeyOptions) (string, error) {
+	h := sha256.New()
+
+	for _, path := range inputPaths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("read input file: %w", err)
+		}
+
+		h.Write(data)
+	}
+
+	encodedOptions, err := json.Marshal(options)
+	if err != nil {
+		return "", fmt.Errorf("marshal cache key options: %w", err)
+	}
+
+	h.Write(encodedOptions)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cacheStatus is reported to the client via the X-Gotenberg-Cache header.
+type cacheStatus string
+
+const (
+	cacheStatusHit    cacheStatus = "hit"
+	cacheStatusMiss   cacheStatus = "miss"
+	cacheStatusBypass cacheStatus = "bypass"
+)
+
+// withConversionCache wraps convert so that a cache hit short-circuits the
+// underlying conversion. c.Response() is tagged with X-Gotenberg-Cache so
+// clients can tell whether the result was served from cache.
+func withConversionCache(c echo.Context, cache gotenberg.ConversionCache, key string, noCache bool, ttl time.Duration, convert func() ([]string, error)) ([]string, error) {
+	if noCache {
+		c.Response().Header().Set("X-Gotenberg-Cache", string(cacheStatusBypass))
+		return convert()
+	}
+
+	paths, err := cache.Get(key)
+	if err == nil {
+		c.Response().Header().Set("X-Gotenberg-Cache", string(cacheStatusHit))
+		return paths, nil
+	}
+
+	if !errors.Is(err, gotenberg.ErrConversionCacheMiss) {
+		return nil, fmt.Errorf("get conversion cache entry: %w", err)
+	}
+
+	c.Response().Header().Set("X-Gotenberg-Cache", string(cacheStatusMiss))
+
+	paths, err = convert()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cache.Put(key, paths, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("put conversion cache entry: %w", err)
+	}
+
+	return paths, nil
+}
+
+// cacheAdminRoute returns an api.Route which deletes a single conversion
+// cache entry by key.
+func cacheAdminRoute(cache gotenberg.ConversionCache) api.Route {
+	return api.Route{
+		Method: http.MethodDelete,
+		Path:   "/cache/:key",
+		Handler: func(c echo.Context) error {
+			err := cache.Delete(c.Param("key"))
+			if err != nil {
+				return fmt.Errorf("delete conversion cache entry: %w", err)
+			}
+
+			return c.NoContent(http.StatusNoContent)
+		},
+	}
+}
@@ -0,0 +1,111 @@
+package libreoffice
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRelevantPostProcessorParams(t *testing.T) {
+	params := map[string]string{
+		"ownerPassword": "secret-a",
+		"userPassword":  "",
+		"watermarkText": "draft",
+	}
+
+	got := relevantPostProcessorParams([]string{"encrypt"}, params)
+
+	if got["ownerPassword"] != "secret-a" {
+		t.Fatalf("expected ownerPassword to be carried over, got %q", got["ownerPassword"])
+	}
+
+	if _, ok := got["watermarkText"]; ok {
+		t.Fatal("watermarkText belongs to the 'watermark' step, which was not requested")
+	}
+}
+
+func TestComputeCacheKeyDiffersByPostProcessorParams(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := dir + "/input.pdf"
+
+	if err := os.WriteFile(inputPath, []byte("%PDF-1.4 test"), 0o644); err != nil {
+		t.Fatalf("write test input: %v", err)
+	}
+
+	base := cacheKeyOptions{PostProcessors: []string{"encrypt"}}
+
+	keyA, err := computeCacheKey([]string{inputPath}, withPostProcessorParams(base, map[string]string{"ownerPassword": "alice"}))
+	if err != nil {
+		t.Fatalf("compute cache key A: %v", err)
+	}
+
+	keyB, err := computeCacheKey([]string{inputPath}, withPostProcessorParams(base, map[string]string{"ownerPassword": "bob"}))
+	if err != nil {
+		t.Fatalf("compute cache key B: %v", err)
+	}
+
+	if keyA == keyB {
+		t.Fatal("expected different encrypt passwords to produce different cache keys")
+	}
+}
+
+func withPostProcessorParams(options cacheKeyOptions, params map[string]string) cacheKeyOptions {
+	options.PostProcessorParams = params
+	return options
+}
+
+func TestComputeCacheKeyDiffersByTemplate(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := dir + "/input.pdf"
+
+	if err := os.WriteFile(inputPath, []byte("%PDF-1.4 test"), 0o644); err != nil {
+		t.Fatalf("write test input: %v", err)
+	}
+
+	keyA, err := computeCacheKey([]string{inputPath}, cacheKeyOptions{Template: "letterhead"})
+	if err != nil {
+		t.Fatalf("compute cache key A: %v", err)
+	}
+
+	keyB, err := computeCacheKey([]string{inputPath}, cacheKeyOptions{Template: "invoice"})
+	if err != nil {
+		t.Fatalf("compute cache key B: %v", err)
+	}
+
+	if keyA == keyB {
+		t.Fatal("expected different templates to produce different cache keys")
+	}
+}
+
+// TestComputeCacheKeyDiffersByTemplateContentHash guards against the cache
+// key folding in only the template name: updating a template's contents in
+// place (the whole point of the templates package's hot-reloading watcher)
+// must still bust the cache even though the name stays the same.
+func TestComputeCacheKeyDiffersByTemplateContentHash(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := dir + "/input.pdf"
+
+	if err := os.WriteFile(inputPath, []byte("%PDF-1.4 test"), 0o644); err != nil {
+		t.Fatalf("write test input: %v", err)
+	}
+
+	base := cacheKeyOptions{Template: "letterhead"}
+
+	keyA, err := computeCacheKey([]string{inputPath}, withTemplateContentHash(base, "hash-a"))
+	if err != nil {
+		t.Fatalf("compute cache key A: %v", err)
+	}
+
+	keyB, err := computeCacheKey([]string{inputPath}, withTemplateContentHash(base, "hash-b"))
+	if err != nil {
+		t.Fatalf("compute cache key B: %v", err)
+	}
+
+	if keyA == keyB {
+		t.Fatal("expected a changed template content hash to produce a different cache key even though the template name didn't change")
+	}
+}
+
+func withTemplateContentHash(options cacheKeyOptions, hash string) cacheKeyOptions {
+	options.TemplateContentHash = hash
+	return options
+}
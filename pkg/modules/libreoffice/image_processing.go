@@ -0,0 +1,171 @@
+package libreoffice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+	"golang.org/x/image/draw"
+	"go.uber.org/zap"
+)
+
+var (
+	cwebpBinPathOnce sync.Once
+	cwebpBinPathVal  string
+	cwebpBinPathErr  error
+)
+
+// resolveCwebpBinPath lazily resolves and caches the path to the cwebp
+// binary used by encodeWebP.
+func resolveCwebpBinPath() (string, error) {
+	cwebpBinPathOnce.Do(func() {
+		cwebpBinPathVal, cwebpBinPathErr = exec.LookPath("cwebp")
+	})
+
+	return cwebpBinPathVal, cwebpBinPathErr
+}
+
+// decodeImage opens the image at path and decodes it, relying on the
+// content sniffing performed by image.Decode rather than the extension.
+func decodeImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open image: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+
+	return img, nil
+}
+
+// cropImage returns the sub-image delimited by rect, clamped to the bounds
+// of img so an out-of-range crop cannot panic.
+func cropImage(img image.Image, rect CropRect) (image.Image, error) {
+	if rect.Width <= 0 || rect.Height <= 0 {
+		return nil, errors.New("crop width and height must be greater than zero")
+	}
+
+	bounds := img.Bounds()
+	region := image.Rect(rect.X, rect.Y, rect.X+rect.Width, rect.Y+rect.Height).Intersect(bounds)
+
+	if region.Empty() {
+		return nil, errors.New("crop rectangle does not intersect the image")
+	}
+
+	type subImager interface {
+		SubImage(r image.Rectangle) image.Image
+	}
+
+	sub, ok := img.(subImager)
+	if !ok {
+		return nil, errors.New("image format does not support cropping")
+	}
+
+	return sub.SubImage(region), nil
+}
+
+// resizeImage scales img so that it fits within width x height, preserving
+// the aspect ratio when only one of the two dimensions is provided.
+func resizeImage(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+
+	if width == 0 {
+		width = bounds.Dx() * height / bounds.Dy()
+	}
+
+	if height == 0 {
+		height = bounds.Dy() * width / bounds.Dx()
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+
+	return dst
+}
+
+// encodeImage writes img to outputPath using the requested format. quality
+// is only honored by the jpeg and webp encoders.
+func encodeImage(ctx context.Context, logger *zap.Logger, outputPath string, img image.Image, format string, quality int) error {
+	switch format {
+	case "png":
+		return encodePNG(outputPath, img)
+	case "jpeg":
+		return encodeJPEG(outputPath, img, quality)
+	case "webp":
+		return encodeWebP(ctx, logger, outputPath, img, quality)
+	default:
+		return fmt.Errorf("unsupported image format '%s'", format)
+	}
+}
+
+func encodePNG(outputPath string, img image.Image) error {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create output file: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	return png.Encode(f, img)
+}
+
+func encodeJPEG(outputPath string, img image.Image, quality int) error {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create output file: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	return jpeg.Encode(f, img, &jpeg.Options{Quality: quality})
+}
+
+// encodeWebP re-encodes img to WebP via the cwebp binary shipped in the
+// Gotenberg Docker image; golang.org/x/image/webp only provides a decoder.
+func encodeWebP(ctx context.Context, logger *zap.Logger, outputPath string, img image.Image, quality int) error {
+	intermediatePath := outputPath + ".png"
+
+	err := encodePNG(intermediatePath, img)
+	if err != nil {
+		return fmt.Errorf("encode intermediate PNG: %w", err)
+	}
+
+	defer func() {
+		_ = os.Remove(intermediatePath)
+	}()
+
+	cwebpBinPath, err := resolveCwebpBinPath()
+	if err != nil {
+		return fmt.Errorf("lookup cwebp path: %w", err)
+	}
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, cwebpBinPath, "-quiet", "-q", fmt.Sprintf("%d", quality), intermediatePath, "-o", outputPath)
+	if err != nil {
+		return fmt.Errorf("create cwebp command: %w", err)
+	}
+
+	exitCode, err := cmd.Exec()
+	if err != nil {
+		return fmt.Errorf("cwebp exited with code %d: %w", exitCode, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package libreoffice
+
+import (
+	"image"
+	"testing"
+)
+
+func TestCropImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+
+	cropped, err := cropImage(img, CropRect{X: 2, Y: 2, Width: 4, Height: 4})
+	if err != nil {
+		t.Fatalf("crop image: %v", err)
+	}
+
+	bounds := cropped.Bounds()
+	if bounds.Dx() != 4 || bounds.Dy() != 4 {
+		t.Fatalf("expected a 4x4 crop, got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+}
+
+func TestCropImageOutOfRange(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+
+	_, err := cropImage(img, CropRect{X: 20, Y: 20, Width: 4, Height: 4})
+	if err == nil {
+		t.Fatal("expected an error for a crop rectangle outside the image bounds")
+	}
+}
+
+func TestResizeImagePreservesAspectRatio(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 50))
+
+	resized := resizeImage(img, 50, 0)
+
+	bounds := resized.Bounds()
+	if bounds.Dx() != 50 || bounds.Dy() != 25 {
+		t.Fatalf("expected a 50x25 image, got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+}
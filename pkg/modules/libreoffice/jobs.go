@@ -0,0 +1,124 @@
+package libreoffice
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v7/pkg/modules/api"
+	"github.com/labstack/echo/v4"
+)
+
+// jobsRoute returns an api.Route which reports the status of an async job
+// submitted to the convert or generate-thumbnail routes.
+func jobsRoute(store gotenberg.JobStore) api.Route {
+	return api.Route{
+		Method: http.MethodGet,
+		Path:   "/jobs/:id",
+		Handler: func(c echo.Context) error {
+			job, err := store.Get(c.Param("id"))
+			if err != nil {
+				if errors.Is(err, gotenberg.ErrJobNotFound) {
+					return api.WrapError(
+						err,
+						api.NewSentinelHTTPError(http.StatusNotFound, fmt.Sprintf("No job found with ID '%s'", c.Param("id"))),
+					)
+				}
+
+				return fmt.Errorf("get job: %w", err)
+			}
+
+			return c.JSON(http.StatusOK, map[string]interface{}{
+				"jobId":  job.ID,
+				"status": job.Status,
+				"error":  job.Error,
+			})
+		},
+	}
+}
+
+// jobResultRoute returns an api.Route which streams back the file(s)
+// produced by a completed async job. job.ResultPaths live in the job's own
+// durable workspace directory (see newJobWorkspace), independent from
+// whichever request happens to call this route, so they are served
+// directly rather than through this request's own api.Context.
+func jobResultRoute(store gotenberg.JobStore) api.Route {
+	return api.Route{
+		Method: http.MethodGet,
+		Path:   "/jobs/:id/result",
+		Handler: func(c echo.Context) error {
+			job, err := store.Get(c.Param("id"))
+			if err != nil {
+				if errors.Is(err, gotenberg.ErrJobNotFound) {
+					return api.WrapError(
+						err,
+						api.NewSentinelHTTPError(http.StatusNotFound, fmt.Sprintf("No job found with ID '%s'", c.Param("id"))),
+					)
+				}
+
+				return fmt.Errorf("get job: %w", err)
+			}
+
+			if job.Status != gotenberg.JobStatusDone {
+				return api.WrapError(
+					fmt.Errorf("job is not done yet (status: %s)", job.Status),
+					api.NewSentinelHTTPError(http.StatusConflict, fmt.Sprintf("Job '%s' is not done yet", job.ID)),
+				)
+			}
+
+			if len(job.ResultPaths) == 1 {
+				return c.Attachment(job.ResultPaths[0], filepath.Base(job.ResultPaths[0]))
+			}
+
+			return serveResultPathsAsZip(c, job.ResultPaths)
+		},
+	}
+}
+
+// serveResultPathsAsZip streams paths back to the client as a single zip
+// archive, written directly to the response so that none of it needs to be
+// staged on disk first.
+func serveResultPathsAsZip(c echo.Context, paths []string) error {
+	c.Response().Header().Set("Content-Type", "application/zip")
+	c.Response().Header().Set("Content-Disposition", `attachment; filename="result.zip"`)
+	c.Response().WriteHeader(http.StatusOK)
+
+	zw := zip.NewWriter(c.Response())
+	defer func() {
+		_ = zw.Close()
+	}()
+
+	for _, path := range paths {
+		err := addFileToZip(zw, path)
+		if err != nil {
+			return fmt.Errorf("add '%s' to result zip: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func addFileToZip(zw *zip.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open file: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	w, err := zw.Create(filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("create zip entry: %w", err)
+	}
+
+	_, err = io.Copy(w, f)
+
+	return err
+}
@@ -0,0 +1,106 @@
+package libreoffice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+	"go.uber.org/zap"
+)
+
+// postProcessorParamKeys lists the form fields forwarded to post-processors
+// as their params, keyed by the step name they are forwarded to. The
+// optimize step receives optimizeImageQuality but does not read it.
+var postProcessorParamKeys = map[string][]string{
+	"watermark": {"watermarkText", "watermarkImage", "watermarkOpacity"},
+	"encrypt":   {"ownerPassword", "userPassword", "permissions"},
+	"attach":    {"attachments"},
+	"optimize":  {"optimizeImageQuality"},
+}
+
+// resolvePostProcessors looks up, in order, the gotenberg.PDFPostProcessor
+// registered for each requested step name.
+func resolvePostProcessors(providers []gotenberg.PDFPostProcessorProvider, names []string) ([]gotenberg.PDFPostProcessor, error) {
+	processors := make([]gotenberg.PDFPostProcessor, len(names))
+
+	for i, name := range names {
+		var (
+			processor gotenberg.PDFPostProcessor
+			err       error
+		)
+
+		for _, provider := range providers {
+			processor, err = provider.PDFPostProcessor(name)
+			if err == nil {
+				break
+			}
+		}
+
+		if processor == nil {
+			return nil, fmt.Errorf("resolve PDF post-processor '%s': %w", name, gotenberg.ErrPDFPostProcessorNotAvailable)
+		}
+
+		processors[i] = processor
+	}
+
+	return processors, nil
+}
+
+// runPostProcessors runs each processor in order, feeding the output of one
+// as the input of the next, and returns the final output path.
+func runPostProcessors(ctx context.Context, logger *zap.Logger, generatePath func(extension string) string, processors []gotenberg.PDFPostProcessor, names []string, params map[string]string) (string, string, error) {
+	outputPath := ""
+
+	for i, processor := range processors {
+		name := names[i]
+
+		stepInputPath := params["__inputPath"]
+		if outputPath != "" {
+			stepInputPath = outputPath
+		}
+
+		outputPath = generatePath(".pdf")
+
+		err := processor.Process(ctx, logger, stepInputPath, outputPath, stepParams(name, params))
+		if err != nil {
+			return "", name, fmt.Errorf("run PDF post-processor '%s': %w", name, err)
+		}
+	}
+
+	return outputPath, "", nil
+}
+
+// stepParams extracts, from the full form params, only the ones relevant to
+// the given post-processing step.
+func stepParams(name string, params map[string]string) map[string]string {
+	stepParams := make(map[string]string)
+
+	for _, key := range postProcessorParamKeys[name] {
+		if value, ok := params[key]; ok {
+			stepParams[key] = value
+		}
+	}
+
+	return stepParams
+}
+
+// parsePostProcessors splits the comma-separated "postProcessors" form
+// field into an ordered, trimmed list of step names.
+func parsePostProcessors(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+
+	parts := strings.Split(raw, ",")
+	names := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		name := strings.TrimSpace(part)
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
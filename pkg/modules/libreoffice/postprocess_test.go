@@ -0,0 +1,35 @@
+package libreoffice
+
+import "testing"
+
+func TestParsePostProcessors(t *testing.T) {
+	got := parsePostProcessors(" encrypt, watermark ,attach")
+
+	want := []string{"encrypt", "watermark", "attach"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestParsePostProcessorsEmpty(t *testing.T) {
+	if got := parsePostProcessors(""); got != nil {
+		t.Fatalf("expected nil for an empty string, got %v", got)
+	}
+}
+
+func TestStepParamsIgnoresUnknownStep(t *testing.T) {
+	params := map[string]string{"ownerPassword": "secret"}
+
+	// "redact" and "sign" are not (yet) registered processors, so they carry
+	// no recognized param keys and must not leak unrelated form fields.
+	got := stepParams("redact", params)
+	if len(got) != 0 {
+		t.Fatalf("expected no params for an unregistered step, got %v", got)
+	}
+}
@@ -0,0 +1,233 @@
+package libreoffice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v7/pkg/modules/libreoffice/uno"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// rasterImageFormats lists the output image formats the rasterize route
+// accepts for the "format" form field, mapped to their file extension.
+var rasterImageFormats = map[string]string{
+	"png":  ".png",
+	"jpeg": ".jpeg",
+	"webp": ".webp",
+}
+
+// rasterizerAPI is satisfied by a uno.API implementation that is also able
+// to render a document's pages to raster images. It is kept separate from
+// uno.API so that callers not interested in rasterization are not forced to
+// depend on it.
+//
+// NOTE: as of this writing, the uno package vendored into this module does
+// not ship a concrete implementation exposing Image. Routes() only appends
+// rasterizeRoute when the configured backend satisfies this interface, so
+// this route is simply absent rather than shipped as a guaranteed 501.
+// Adding the LibreOffice-side rendering (an "export to image" UNO call,
+// analogous to the existing PDF export) belongs in the uno package itself;
+// it is out of scope for this module and is not faked here. The ok-check
+// below is kept as a defensive guard for callers that construct this route
+// directly instead of going through Routes().
+type rasterizerAPI interface {
+	uno.API
+	Image(logger *zap.Logger, inputPath, outputPath string, options uno.Options) error
+}
+
+// rasterizeRoute returns an api.Route which can rasterize LibreOffice
+// documents to images. Each input document yields exactly one image: Image
+// renders it into a single file, which is then post-processed.
+func rasterizeRoute(unoAPI uno.API) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/libreoffice/rasterize",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			rasterAPI, ok := unoAPI.(rasterizerAPI)
+			if !ok {
+				return api.WrapError(
+					errors.New("rasterize route: uno API does not support rasterization"),
+					api.NewSentinelHTTPError(http.StatusNotImplemented, "Rasterization is not supported by the configured LibreOffice backend"),
+				)
+			}
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths []string
+				format     string
+				quality    int
+				width      int
+				height     int
+				cropX      int
+				cropY      int
+				cropW      int
+				cropH      int
+				dpi        int
+				pageRanges string
+				landscape  bool
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths(unoAPI.Extensions(), &inputPaths).
+				String("format", &format, "png").
+				Int("quality", &quality, 90).
+				Int("width", &width, 0).
+				Int("height", &height, 0).
+				Int("cropX", &cropX, 0).
+				Int("cropY", &cropY, 0).
+				Int("cropW", &cropW, 0).
+				Int("cropH", &cropH, 0).
+				Int("dpi", &dpi, 0).
+				String("pageRanges", &pageRanges, "").
+				Bool("landscape", &landscape, false).
+				Validate()
+
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			extension, ok := rasterImageFormats[format]
+			if !ok {
+				return api.WrapError(
+					fmt.Errorf("rasterize route: unknown format '%s'", format),
+					api.NewSentinelHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown image format '%s' (format); expected png, jpeg or webp", format)),
+				)
+			}
+
+			if quality < 1 || quality > 100 {
+				return api.WrapError(
+					errors.New("rasterize route: quality out of range"),
+					api.NewSentinelHTTPError(http.StatusBadRequest, "The 'quality' form field must be between 1 and 100"),
+				)
+			}
+
+			crop := CropRect{X: cropX, Y: cropY, Width: cropW, Height: cropH}
+
+			// Alright, let's rasterize each document, one output path per
+			// input.
+
+			var outputPaths []string
+
+			for _, inputPath := range inputPaths {
+				renderPath := ctx.GeneratePath(extension)
+
+				options := uno.Options{
+					Landscape:  landscape,
+					PageRanges: pageRanges,
+				}
+
+				err = rasterAPI.Image(ctx.Log(), inputPath, renderPath, options)
+				if err != nil {
+					if errors.Is(err, uno.ErrMalformedPageRanges) {
+						return api.WrapError(
+							fmt.Errorf("rasterize to image: %w", err),
+							api.NewSentinelHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed page ranges '%s' (pageRanges)", pageRanges)),
+						)
+					}
+
+					return fmt.Errorf("rasterize to image: %w", err)
+				}
+
+				processedPath := ctx.GeneratePath(extension)
+
+				err = ProcessRasterImage(ctx, ctx.Log(), renderPath, processedPath, RasterOptions{
+					Format:  format,
+					Quality: quality,
+					Width:   width,
+					Height:  height,
+					Crop:    crop,
+					Dpi:     dpi,
+				})
+				if err != nil {
+					return fmt.Errorf("process raster image: %w", err)
+				}
+
+				outputPaths = append(outputPaths, processedPath)
+			}
+
+			// Last but not least, add the output paths to the context so
+			// that the API is able to send them (as a zip if there is more
+			// than one) to the client.
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// CropRect describes a crop rectangle expressed in pixels. A zero value
+// means no cropping is applied.
+type CropRect struct {
+	X, Y, Width, Height int
+}
+
+func (c CropRect) isZero() bool {
+	return c.Width == 0 && c.Height == 0
+}
+
+// RasterOptions describes the post-processing to apply to a raw page
+// render before it is returned to the client.
+type RasterOptions struct {
+	Format  string
+	Quality int
+	Width   int
+	Height  int
+	Crop    CropRect
+	Dpi     int
+}
+
+// rasterBaselineDPI is the resolution LibreOffice rasterizes pages at when
+// no explicit width/height is requested, used to turn the "dpi" form field
+// into a concrete scale factor.
+const rasterBaselineDPI = 96
+
+// ProcessRasterImage resizes, crops and re-encodes the image at inputPath,
+// writing the result to outputPath in the requested format. The decoding,
+// cropping, resizing and encoding primitives live in image_processing.go.
+func ProcessRasterImage(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options RasterOptions) error {
+	img, err := decodeImage(inputPath)
+	if err != nil {
+		return fmt.Errorf("decode image: %w", err)
+	}
+
+	if !options.Crop.isZero() {
+		img, err = cropImage(img, options.Crop)
+		if err != nil {
+			return fmt.Errorf("crop image: %w", err)
+		}
+	}
+
+	width, height := options.Width, options.Height
+
+	// When the caller gives an explicit dpi but no explicit width/height,
+	// scale the rendered page relative to rasterBaselineDPI instead of
+	// silently ignoring the field.
+	if options.Dpi > 0 && width == 0 && height == 0 {
+		bounds := img.Bounds()
+		ratio := float64(options.Dpi) / float64(rasterBaselineDPI)
+		width = int(float64(bounds.Dx()) * ratio)
+		height = int(float64(bounds.Dy()) * ratio)
+	}
+
+	if width > 0 || height > 0 {
+		img = resizeImage(img, width, height)
+	}
+
+	err = encodeImage(ctx, logger, outputPath, img, options.Format, options.Quality)
+	if err != nil {
+		return fmt.Errorf("encode image: %w", err)
+	}
+
+	return nil
+}
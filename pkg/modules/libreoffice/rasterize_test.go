@@ -0,0 +1,84 @@
+package libreoffice
+
+import (
+	"context"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestProcessRasterImageScalesByDPI(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "input.png")
+	outputPath := filepath.Join(dir, "output.png")
+
+	f, err := os.Create(inputPath)
+	if err != nil {
+		t.Fatalf("create input file: %v", err)
+	}
+
+	err = png.Encode(f, image.NewRGBA(image.Rect(0, 0, 96, 96)))
+	_ = f.Close()
+	if err != nil {
+		t.Fatalf("encode input image: %v", err)
+	}
+
+	err = ProcessRasterImage(context.Background(), zap.NewNop(), inputPath, outputPath, RasterOptions{
+		Format: "png",
+		Dpi:    192,
+	})
+	if err != nil {
+		t.Fatalf("process raster image: %v", err)
+	}
+
+	out, err := decodeImage(outputPath)
+	if err != nil {
+		t.Fatalf("decode output image: %v", err)
+	}
+
+	bounds := out.Bounds()
+	if bounds.Dx() != 192 || bounds.Dy() != 192 {
+		t.Fatalf("expected dpi 192 (2x baseline) to double the 96x96 input, got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+}
+
+func TestProcessRasterImageExplicitDimensionsWinOverDPI(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "input.png")
+	outputPath := filepath.Join(dir, "output.png")
+
+	f, err := os.Create(inputPath)
+	if err != nil {
+		t.Fatalf("create input file: %v", err)
+	}
+
+	err = png.Encode(f, image.NewRGBA(image.Rect(0, 0, 96, 96)))
+	_ = f.Close()
+	if err != nil {
+		t.Fatalf("encode input image: %v", err)
+	}
+
+	err = ProcessRasterImage(context.Background(), zap.NewNop(), inputPath, outputPath, RasterOptions{
+		Format: "png",
+		Width:  10,
+		Height: 10,
+		Dpi:    192,
+	})
+	if err != nil {
+		t.Fatalf("process raster image: %v", err)
+	}
+
+	out, err := decodeImage(outputPath)
+	if err != nil {
+		t.Fatalf("decode output image: %v", err)
+	}
+
+	bounds := out.Bounds()
+	if bounds.Dx() != 10 || bounds.Dy() != 10 {
+		t.Fatalf("expected explicit width/height to take precedence over dpi, got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+}
@@ -5,17 +5,45 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
 	"github.com/gotenberg/gotenberg/v7/pkg/modules/api"
 	"github.com/gotenberg/gotenberg/v7/pkg/modules/libreoffice/uno"
+	"github.com/gotenberg/gotenberg/v7/pkg/modules/templates"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 )
 
+// Routes returns every api.Route exposed by this module, so that callers
+// only have to register one slice instead of remembering to wire each route
+// individually (a rasterize route added here but forgotten below would
+// otherwise be unreachable).
+//
+// The rasterize route is only included when unoAPI actually implements
+// rasterizerAPI: no concrete uno.API backend in this module renders pages to
+// raster images yet, and advertising /forms/libreoffice/rasterize for a
+// backend that can never serve it would mean every request fails with a
+// 501. Once a backend implements Image(), the route appears automatically.
+func Routes(unoAPI uno.API, engine gotenberg.PDFEngine, postProcessorProviders []gotenberg.PDFPostProcessorProvider, jobStore gotenberg.JobStore, tenantLimiter *tenantConcurrencyLimiter, cache gotenberg.ConversionCache, cacheTTL time.Duration, templateIndex *templates.Index) []api.Route {
+	routes := []api.Route{
+		convertRoute(unoAPI, engine, postProcessorProviders, jobStore, tenantLimiter, cache, cacheTTL, templateIndex),
+		generateThumnailRoute(unoAPI, engine, jobStore, tenantLimiter, cache, cacheTTL),
+		jobsRoute(jobStore),
+		jobResultRoute(jobStore),
+		cacheAdminRoute(cache),
+	}
+
+	if _, ok := unoAPI.(rasterizerAPI); ok {
+		routes = append(routes, rasterizeRoute(unoAPI))
+	}
+
+	return routes
+}
+
 // convertRoute returns an api.Route which can convert LibreOffice documents
 // to PDF.
-func convertRoute(unoAPI uno.API, engine gotenberg.PDFEngine) api.Route {
+func convertRoute(unoAPI uno.API, engine gotenberg.PDFEngine, postProcessorProviders []gotenberg.PDFPostProcessorProvider, jobStore gotenberg.JobStore, tenantLimiter *tenantConcurrencyLimiter, cache gotenberg.ConversionCache, cacheTTL time.Duration, templateIndex *templates.Index) api.Route {
 	return api.Route{
 		Method:      http.MethodPost,
 		Path:        "/forms/libreoffice/convert",
@@ -32,6 +60,17 @@ func convertRoute(unoAPI uno.API, engine gotenberg.PDFEngine) api.Route {
 				nativePDFformat    string
 				PDFformat          string
 				merge              bool
+				postProcessorsRaw  string
+				watermarkText      string
+				watermarkImage     string
+				watermarkOpacity   string
+				ownerPassword      string
+				userPassword       string
+				permissions        string
+				attachments        string
+				optimizeQuality    string
+				noCache            bool
+				template           string
 			)
 
 			err := ctx.FormData().
@@ -42,6 +81,17 @@ func convertRoute(unoAPI uno.API, engine gotenberg.PDFEngine) api.Route {
 				String("nativePdfFormat", &nativePDFformat, "").
 				String("pdfFormat", &PDFformat, "").
 				Bool("merge", &merge, false).
+				String("postProcessors", &postProcessorsRaw, "").
+				String("watermarkText", &watermarkText, "").
+				String("watermarkImage", &watermarkImage, "").
+				String("watermarkOpacity", &watermarkOpacity, "").
+				String("ownerPassword", &ownerPassword, "").
+				String("userPassword", &userPassword, "").
+				String("permissions", &permissions, "").
+				String("attachments", &attachments, "").
+				String("optimizeImageQuality", &optimizeQuality, "").
+				Bool("noCache", &noCache, false).
+				String("template", &template, "").
 				Validate()
 
 			if err != nil {
@@ -77,122 +127,257 @@ func convertRoute(unoAPI uno.API, engine gotenberg.PDFEngine) api.Route {
 				nativePDFformat = gotenberg.FormatPDFA1a
 			}
 
-			// Alright, let's convert each document to PDF.
+			// Let's also resolve the post-processing pipeline, if any, so
+			// that both the merge and non-merge paths below can apply it to
+			// their respective output path(s).
 
-			outputPaths := make([]string, len(inputPaths))
+			postProcessorNames := parsePostProcessors(postProcessorsRaw)
 
-			for i, inputPath := range inputPaths {
-				outputPaths[i] = ctx.GeneratePath(".pdf")
+			postProcessors, err := resolvePostProcessors(postProcessorProviders, postProcessorNames)
+			if err != nil {
+				return api.WrapError(
+					err,
+					api.NewSentinelHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown or unavailable post-processor in '%s' (postProcessors)", postProcessorsRaw)),
+				)
+			}
+
+			postProcessorParams := map[string]string{
+				"watermarkText":        watermarkText,
+				"watermarkImage":       watermarkImage,
+				"watermarkOpacity":     watermarkOpacity,
+				"ownerPassword":        ownerPassword,
+				"userPassword":         userPassword,
+				"permissions":          permissions,
+				"attachments":          attachments,
+				"optimizeImageQuality": optimizeQuality,
+			}
 
-				options := uno.Options{
-					Landscape:  landscape,
-					PageRanges: nativePageRanges,
-					PDFformat:  nativePDFformat,
+			applyPostProcessors := func(workCtx context.Context, generatePath func(string) string, logger *zap.Logger, inputPath string) (string, error) {
+				if len(postProcessorNames) == 0 {
+					return inputPath, nil
 				}
 
-				err = unoAPI.PDF(ctx, ctx.Log(), inputPath, outputPaths[i], options)
+				params := make(map[string]string, len(postProcessorParams)+1)
+				for k, v := range postProcessorParams {
+					params[k] = v
+				}
+				params["__inputPath"] = inputPath
 
+				outputPath, failedStep, err := runPostProcessors(workCtx, logger, generatePath, postProcessors, postProcessorNames, params)
 				if err != nil {
-					if errors.Is(err, uno.ErrMalformedPageRanges) {
-						return api.WrapError(
-							fmt.Errorf("convert to PDF: %w", err),
-							api.NewSentinelHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed page ranges '%s' (nativePageRanges)", options.PageRanges)),
-						)
-					}
-
-					return fmt.Errorf("convert to PDF: %w", err)
+					return "", api.WrapError(
+						err,
+						api.NewSentinelHTTPError(http.StatusBadRequest, fmt.Sprintf("Post-processor '%s' failed to process the PDF", failedStep)),
+					)
 				}
+
+				return outputPath, nil
 			}
 
-			// So far so good, let's check if we have to merge the PDFs. Quick
-			// win: if there is only one PDF, skip this step.
+			// Resolved once up front, rather than inside convert, so that its
+			// ContentHash is available for the cache key below even on a
+			// cache hit (where convert never runs).
+			var resolvedTemplate templates.Template
 
-			if len(outputPaths) > 1 && merge {
-				outputPath := ctx.GeneratePath(".pdf")
+			if template != "" {
+				var err error
 
-				err = engine.Merge(ctx, ctx.Log(), outputPaths, outputPath)
+				resolvedTemplate, err = resolveTemplate(templateIndex, template)
 				if err != nil {
-					return fmt.Errorf("merge PDFs: %w", err)
+					return err
 				}
+			}
 
-				// Now, let's check if the client want to convert this result
-				// PDF to a specific PDF format.
+			// convert runs the actual conversion (document -> PDF, optional
+			// merge, optional PDF/A conversion, post-processing) and
+			// returns the resulting output paths. It is extracted into a
+			// closure so that it can run either inline for a synchronous
+			// request (workCtx/generatePath/logger/workInputPaths mirroring
+			// ctx/ctx.GeneratePath/ctx.Log()/inputPaths), or in the
+			// background for an async one, against that job's own durable
+			// workspace instead of this request's api.Context.
+			convert := func(workCtx context.Context, generatePath func(string) string, logger *zap.Logger, workInputPaths []string) ([]string, error) {
+				outputPaths := make([]string, len(workInputPaths))
 
-				// Note: nativePdfA1aFormat/nativePdfFormat have not been
-				// specified if PDFformat is not empty.
+				var templateProfileDir string
 
-				if PDFformat != "" {
-					convertInputPath := outputPath
-					convertOutputPath := ctx.GeneratePath(".pdf")
+				if template != "" {
+					var err error
+
+					templateProfileDir, err = resolveTemplateProfile(generatePath, resolvedTemplate)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				for i, inputPath := range workInputPaths {
+					outputPaths[i] = generatePath(".pdf")
+
+					options := uno.Options{
+						Landscape:   landscape,
+						PageRanges:  nativePageRanges,
+						PDFformat:   nativePDFformat,
+						UserProfile: templateProfileDir,
+					}
 
-					err = engine.Convert(ctx, ctx.Log(), PDFformat, convertInputPath, convertOutputPath)
+					err := unoAPI.PDF(workCtx, logger, inputPath, outputPaths[i], options)
 
 					if err != nil {
-						if errors.Is(err, gotenberg.ErrPDFFormatNotAvailable) {
-							return api.WrapError(
-								fmt.Errorf("convert PDF: %w", err),
-								api.NewSentinelHTTPError(
-									http.StatusBadRequest,
-									fmt.Sprintf("At least one PDF engine does not handle the PDF format '%s' (pdfFormat), while other have failed to convert for other reasons", PDFformat),
-								),
+						if errors.Is(err, uno.ErrMalformedPageRanges) {
+							return nil, api.WrapError(
+								fmt.Errorf("convert to PDF: %w", err),
+								api.NewSentinelHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed page ranges '%s' (nativePageRanges)", options.PageRanges)),
 							)
 						}
 
-						return fmt.Errorf("convert PDF: %w", err)
+						return nil, fmt.Errorf("convert to PDF: %w", err)
 					}
-
-					// Important: the output path is now the converted file.
-					outputPath = convertOutputPath
 				}
 
-				// Last but not least, add the output path to the context so that
-				// the API is able to send it as a response to the client.
+				// So far so good, let's check if we have to merge the PDFs.
+				// Quick win: if there is only one PDF, skip this step.
 
-				err = ctx.AddOutputPaths(outputPath)
-				if err != nil {
-					return fmt.Errorf("add output path: %w", err)
-				}
+				if len(outputPaths) > 1 && merge {
+					outputPath := generatePath(".pdf")
 
-				return nil
-			}
+					err := engine.Merge(workCtx, logger, outputPaths, outputPath)
+					if err != nil {
+						return nil, fmt.Errorf("merge PDFs: %w", err)
+					}
 
-			// Ok, we don't have to merge the PDFs. Let's check if the client
-			// want to convert each PDF to a specific PDF format.
+					// Now, let's check if the client want to convert this
+					// result PDF to a specific PDF format.
 
-			// Note: nativePdfA1aFormat/nativePdfFormat have not been
-			// specified if PDFformat is not empty.
+					// Note: nativePdfA1aFormat/nativePdfFormat have not been
+					// specified if PDFformat is not empty.
 
-			if PDFformat != "" {
-				convertOutputPaths := make([]string, len(outputPaths))
+					if PDFformat != "" {
+						convertInputPath := outputPath
+						convertOutputPath := generatePath(".pdf")
 
-				for i, outputPath := range outputPaths {
-					convertInputPath := outputPath
-					convertOutputPaths[i] = ctx.GeneratePath(".pdf")
+						err = engine.Convert(workCtx, logger, PDFformat, convertInputPath, convertOutputPath)
 
-					err = engine.Convert(ctx, ctx.Log(), PDFformat, convertInputPath, convertOutputPaths[i])
+						if err != nil {
+							if errors.Is(err, gotenberg.ErrPDFFormatNotAvailable) {
+								return nil, api.WrapError(
+									fmt.Errorf("convert PDF: %w", err),
+									api.NewSentinelHTTPError(
+										http.StatusBadRequest,
+										fmt.Sprintf("At least one PDF engine does not handle the PDF format '%s' (pdfFormat), while other have failed to convert for other reasons", PDFformat),
+									),
+								)
+							}
 
-					if err != nil {
-						if errors.Is(err, gotenberg.ErrPDFFormatNotAvailable) {
-							return api.WrapError(
-								fmt.Errorf("convert PDF: %w", err),
-								api.NewSentinelHTTPError(
-									http.StatusBadRequest,
-									fmt.Sprintf("At least one PDF engine does not handle the PDF format '%s' (pdfFormat), while other have failed to convert for other reasons", PDFformat),
-								),
-							)
+							return nil, fmt.Errorf("convert PDF: %w", err)
 						}
 
-						return fmt.Errorf("convert PDF: %w", err)
+						// Important: the output path is now the converted
+						// file.
+						outputPath = convertOutputPath
+					}
+
+					// Now, let's run the post-processing pipeline, if any,
+					// on the resulting PDF.
+
+					outputPath, err = applyPostProcessors(workCtx, generatePath, logger, outputPath)
+					if err != nil {
+						return nil, fmt.Errorf("apply post-processors: %w", err)
 					}
 
+					return []string{outputPath}, nil
 				}
 
-				// Important: the output paths are now the converted files.
-				outputPaths = convertOutputPaths
+				// Ok, we don't have to merge the PDFs. Let's check if the
+				// client want to convert each PDF to a specific PDF format.
+
+				// Note: nativePdfA1aFormat/nativePdfFormat have not been
+				// specified if PDFformat is not empty.
+
+				if PDFformat != "" {
+					convertOutputPaths := make([]string, len(outputPaths))
+
+					for i, outputPath := range outputPaths {
+						convertInputPath := outputPath
+						convertOutputPaths[i] = generatePath(".pdf")
+
+						err := engine.Convert(workCtx, logger, PDFformat, convertInputPath, convertOutputPaths[i])
+
+						if err != nil {
+							if errors.Is(err, gotenberg.ErrPDFFormatNotAvailable) {
+								return nil, api.WrapError(
+									fmt.Errorf("convert PDF: %w", err),
+									api.NewSentinelHTTPError(
+										http.StatusBadRequest,
+										fmt.Sprintf("At least one PDF engine does not handle the PDF format '%s' (pdfFormat), while other have failed to convert for other reasons", PDFformat),
+									),
+								)
+							}
+
+							return nil, fmt.Errorf("convert PDF: %w", err)
+						}
+					}
+
+					// Important: the output paths are now the converted
+					// files.
+					outputPaths = convertOutputPaths
+				}
+
+				// Now, let's run the post-processing pipeline, if any, on
+				// each resulting PDF.
+
+				for i, outputPath := range outputPaths {
+					outputPaths[i], err = applyPostProcessors(workCtx, generatePath, logger, outputPath)
+					if err != nil {
+						return nil, fmt.Errorf("apply post-processors: %w", err)
+					}
+				}
+
+				return outputPaths, nil
 			}
 
-			// Last but not least, add the output paths to the context so that
-			// the API is able to send them as a response to the client.
+			// Let's check if the client asked for an async conversion
+			// before running it.
+
+			asyncReq, err := parseAsyncRequest(c, ctx)
+			if err != nil {
+				return err
+			}
+
+			if asyncReq.enabled {
+				return submitAsyncJob(c, ctx, jobStore, tenantLimiter, asyncReq, inputPaths, convert)
+			}
+
+			// Async requests bypass the cache: they return above, before
+			// any cache key is computed, and their result is served only
+			// through GET /jobs/{id}/result.
+
+			cacheKey, err := computeCacheKey(inputPaths, cacheKeyOptions{
+				Landscape:           landscape,
+				NativePageRanges:    nativePageRanges,
+				NativePDFformat:     nativePDFformat,
+				PDFformat:           PDFformat,
+				Merge:               merge,
+				PostProcessors:      postProcessorNames,
+				PostProcessorParams: relevantPostProcessorParams(postProcessorNames, postProcessorParams),
+				Template:            template,
+				TemplateContentHash: resolvedTemplate.ContentHash,
+				LibreOfficeVersion:  libreOfficeVersion(),
+				UnoconvVersion:      unoconvVersion(),
+			})
+			if err != nil {
+				return fmt.Errorf("compute conversion cache key: %w", err)
+			}
+
+			outputPaths, err := withConversionCache(c, cache, cacheKey, noCache, cacheTTL, func() ([]string, error) {
+				return convert(ctx, ctx.GeneratePath, ctx.Log(), inputPaths)
+			})
+			if err != nil {
+				return err
+			}
+
+			// Last but not least, add the output paths to the context so
+			// that the API is able to send them as a response to the
+			// client.
 
 			err = ctx.AddOutputPaths(outputPaths...)
 			if err != nil {
@@ -204,7 +389,11 @@ func convertRoute(unoAPI uno.API, engine gotenberg.PDFEngine) api.Route {
 	}
 }
 
-func createPNG(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options uno.Options) error {
+// CreatePNG renders the first matching page of inputPath to a PNG at
+// outputPath via unoconv. It is exported so that the grpcapi package's
+// GenerateThumbnail RPC can go through the exact same conversion code as
+// generateThumnailRoute instead of depending on a separate capability.
+func CreatePNG(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options uno.Options) error {
 	args := []string{
 		"--no-launch",
 		"--format",
@@ -298,7 +487,7 @@ func createPNG(ctx context.Context, logger *zap.Logger, inputPath, outputPath st
 }
 
 // generateThumnailRoute returns an api.Route which can generate image thumbnails for LibreOffice documents.
-func generateThumnailRoute(unoAPI uno.API, engine gotenberg.PDFEngine) api.Route {
+func generateThumnailRoute(unoAPI uno.API, engine gotenberg.PDFEngine, jobStore gotenberg.JobStore, tenantLimiter *tenantConcurrencyLimiter, cache gotenberg.ConversionCache, cacheTTL time.Duration) api.Route {
 	return api.Route{
 		Method:      http.MethodPost,
 		Path:        "/forms/libreoffice/generate-thumbnail",
@@ -308,15 +497,17 @@ func generateThumnailRoute(unoAPI uno.API, engine gotenberg.PDFEngine) api.Route
 
 			// Let's get the data from the form and validate them.
 			var (
-				inputPaths         []string
-				landscape          bool
-				nativePageRanges   string
+				inputPaths       []string
+				landscape        bool
+				nativePageRanges string
+				noCache          bool
 			)
 
 			err := ctx.FormData().
 				MandatoryPaths(unoAPI.Extensions(), &inputPaths).
 				Bool("landscape", &landscape, false).
 				String("nativePageRanges", &nativePageRanges, "").
+				Bool("noCache", &noCache, false).
 				Validate()
 
 			if err != nil {
@@ -352,31 +543,70 @@ func generateThumnailRoute(unoAPI uno.API, engine gotenberg.PDFEngine) api.Route
 				nativePDFformat = gotenberg.FormatPDFA1a
 			}
 
-			// Alright, let's convert each document to PNG.
+			// generateThumbnails runs the actual thumbnail generation and
+			// returns the resulting output paths, extracted into a closure
+			// so that it can run either inline for a synchronous request
+			// (workCtx/generatePath/logger/workInputPaths mirroring
+			// ctx/ctx.GeneratePath/ctx.Log()/inputPaths), or in the
+			// background for an async one, against that job's own durable
+			// workspace instead of this request's api.Context.
+			generateThumbnails := func(workCtx context.Context, generatePath func(string) string, logger *zap.Logger, workInputPaths []string) ([]string, error) {
+				outputPaths := make([]string, len(workInputPaths))
+
+				for i, inputPath := range workInputPaths {
+					outputPaths[i] = generatePath(".png")
+
+					options := uno.Options{
+						Landscape:  landscape,
+						PageRanges: 1, // this gets the first page from a document for thumbnails
+						PDFformat:  nativePDFformat,
+					}
 
-			outputPaths := make([]string, len(inputPaths))
+					err := CreatePNG(workCtx, logger, inputPath, outputPaths[i], options)
 
-			for i, inputPath := range inputPaths {
-				outputPaths[i] = ctx.GeneratePath(".png")
+					if err != nil {
+						if errors.Is(err, uno.ErrMalformedPageRanges) {
+							return nil, api.WrapError(
+								fmt.Errorf("created thumbnail: %w", err),
+								api.NewSentinelHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed page ranges '%s' (nativePageRanges)", options.PageRanges)),
+							)
+						}
 
-				options := uno.Options{
-					Landscape:  landscape,
-					PageRanges: 1, // this gets the first page from a document for thumbnails
-					PDFformat:  nativePDFformat,
+						return nil, fmt.Errorf("create thumbnail: %w", err)
+					}
 				}
 
-				err = createPNG(ctx, ctx.Log(), inputPath, outputPaths[i], options)
+				return outputPaths, nil
+			}
 
-				if err != nil {
-					if errors.Is(err, uno.ErrMalformedPageRanges) {
-						return api.WrapError(
-							fmt.Errorf("created thumbnail: %w", err),
-							api.NewSentinelHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed page ranges '%s' (nativePageRanges)", options.PageRanges)),
-						)
-					}
+			// Let's check if the client asked for an async conversion
+			// before running it.
 
-					return fmt.Errorf("create thumbnail: %w", err)
-				}
+			asyncReq, err := parseAsyncRequest(c, ctx)
+			if err != nil {
+				return err
+			}
+
+			if asyncReq.enabled {
+				return submitAsyncJob(c, ctx, jobStore, tenantLimiter, asyncReq, inputPaths, generateThumbnails)
+			}
+
+			cacheKey, err := computeCacheKey(inputPaths, cacheKeyOptions{
+				Landscape:          landscape,
+				NativePageRanges:   nativePageRanges,
+				NativePDFformat:    nativePDFformat,
+				LibreOfficeVersion: libreOfficeVersion(),
+				UnoconvVersion:     unoconvVersion(),
+			})
+			if err != nil {
+				return fmt.Errorf("compute conversion cache key: %w", err)
+			}
+
+			outputPaths, err := withConversionCache(c, cache, cacheKey, noCache, cacheTTL, func() ([]string, error) {
+				return generateThumbnails(ctx, ctx.GeneratePath, ctx.Log(), inputPaths)
+			})
+			if err != nil {
+				return err
 			}
 
 			// Last but not least, add the output paths to the context so that
@@ -0,0 +1,81 @@
+package libreoffice
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v7/pkg/modules/templates"
+)
+
+// resolveTemplate looks up name in the template index, translating
+// templates.ErrTemplateNotFound into a client-facing 400 the same way every
+// other unknown-input case on this route does.
+func resolveTemplate(idx *templates.Index, name string) (templates.Template, error) {
+	template, err := idx.Resolve(name)
+	if err != nil {
+		if errors.Is(err, templates.ErrTemplateNotFound) {
+			return templates.Template{}, api.WrapError(
+				fmt.Errorf("resolve template: %w", err),
+				api.NewSentinelHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown template '%s'", name)),
+			)
+		}
+
+		return templates.Template{}, fmt.Errorf("resolve template: %w", err)
+	}
+
+	return template, nil
+}
+
+// resolveTemplateProfile copies template's backing file into a fresh
+// LibreOffice user profile directory generated by generatePath, so that
+// unoAPI.PDF can point unoconv's --user-profile at it and render the input
+// merged with the shared styles/macros it carries. generatePath is passed
+// in rather than an *api.Context so that an async job can route this into
+// its own durable workspace instead of the submitting request's transient
+// working directory.
+func resolveTemplateProfile(generatePath func(string) string, template templates.Template) (string, error) {
+	profileDir := generatePath("")
+
+	err := os.MkdirAll(profileDir, 0o755)
+	if err != nil {
+		return "", fmt.Errorf("create template profile directory: %w", err)
+	}
+
+	destPath := filepath.Join(profileDir, filepath.Base(template.Path))
+
+	err = copyTemplateFile(template.Path, destPath)
+	if err != nil {
+		return "", fmt.Errorf("copy template into profile directory: %w", err)
+	}
+
+	return profileDir, nil
+}
+
+func copyTemplateFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open template file: %w", err)
+	}
+
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("create template copy: %w", err)
+	}
+
+	defer func() {
+		_ = out.Close()
+	}()
+
+	_, err = io.Copy(out, in)
+
+	return err
+}
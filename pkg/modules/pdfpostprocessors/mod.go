@@ -0,0 +1,110 @@
+// Package pdfpostprocessors provides the built-in gotenberg.PDFPostProcessor
+// implementations (watermark, encrypt, attach and optimize) consumed by
+// routes such as the LibreOffice convert route's post-processing pipeline.
+package pdfpostprocessors
+
+import (
+	"fmt"
+	"os/exec"
+	"sync"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(PDFPostProcessors))
+}
+
+// PDFPostProcessors is a module which provides the built-in
+// gotenberg.PDFPostProcessor implementations. qpdf/pdfcpu are resolved
+// lazily (see qpdfBinPath/pdfcpuBinPath) rather than in Provision, since
+// post-processing is opt-in per request via the "postProcessors" form
+// field: a deployment that never requests watermark/encrypt/attach/optimize
+// should not be unable to start Gotenberg just because one of those two
+// binaries isn't installed.
+type PDFPostProcessors struct {
+	qpdfBinPathOnce sync.Once
+	qpdfBinPathVal  string
+	qpdfBinPathErr  error
+
+	pdfcpuBinPathOnce sync.Once
+	pdfcpuBinPathVal  string
+	pdfcpuBinPathErr  error
+}
+
+// Descriptor returns a PDFPostProcessors' module descriptor.
+func (mod *PDFPostProcessors) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID:  func() string { return "pdf-postprocessors" },
+		New: func() gotenberg.Module { return new(PDFPostProcessors) },
+	}
+}
+
+func (mod *PDFPostProcessors) Provision(_ *gotenberg.Context) error {
+	return nil
+}
+
+// qpdfBinPath resolves and caches the qpdf binary path on first use, rather
+// than in Provision, so that a deployment never requesting "encrypt" is not
+// forced to have qpdf installed.
+func (mod *PDFPostProcessors) qpdfBinPath() (string, error) {
+	mod.qpdfBinPathOnce.Do(func() {
+		mod.qpdfBinPathVal, mod.qpdfBinPathErr = exec.LookPath("qpdf")
+	})
+
+	return mod.qpdfBinPathVal, mod.qpdfBinPathErr
+}
+
+// pdfcpuBinPath mirrors qpdfBinPath for the pdfcpu binary, used by
+// watermark, attach and optimize.
+func (mod *PDFPostProcessors) pdfcpuBinPath() (string, error) {
+	mod.pdfcpuBinPathOnce.Do(func() {
+		mod.pdfcpuBinPathVal, mod.pdfcpuBinPathErr = exec.LookPath("pdfcpu")
+	})
+
+	return mod.pdfcpuBinPathVal, mod.pdfcpuBinPathErr
+}
+
+// PDFPostProcessor returns the gotenberg.PDFPostProcessor registered under
+// name, or an error wrapping gotenberg.ErrPDFPostProcessorNotAvailable.
+func (mod *PDFPostProcessors) PDFPostProcessor(name string) (gotenberg.PDFPostProcessor, error) {
+	switch name {
+	case "watermark":
+		pdfcpuBinPath, err := mod.pdfcpuBinPath()
+		if err != nil {
+			return nil, fmt.Errorf("lookup pdfcpu path: %w", err)
+		}
+
+		return watermarkProcessor{pdfcpuBinPath: pdfcpuBinPath}, nil
+	case "encrypt":
+		qpdfBinPath, err := mod.qpdfBinPath()
+		if err != nil {
+			return nil, fmt.Errorf("lookup qpdf path: %w", err)
+		}
+
+		return encryptProcessor{qpdfBinPath: qpdfBinPath}, nil
+	case "attach":
+		pdfcpuBinPath, err := mod.pdfcpuBinPath()
+		if err != nil {
+			return nil, fmt.Errorf("lookup pdfcpu path: %w", err)
+		}
+
+		return attachProcessor{pdfcpuBinPath: pdfcpuBinPath}, nil
+	case "optimize":
+		pdfcpuBinPath, err := mod.pdfcpuBinPath()
+		if err != nil {
+			return nil, fmt.Errorf("lookup pdfcpu path: %w", err)
+		}
+
+		return optimizeProcessor{pdfcpuBinPath: pdfcpuBinPath}, nil
+	default:
+		return nil, fmt.Errorf("processor '%s': %w", name, gotenberg.ErrPDFPostProcessorNotAvailable)
+	}
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module                   = (*PDFPostProcessors)(nil)
+	_ gotenberg.Provisioner              = (*PDFPostProcessors)(nil)
+	_ gotenberg.PDFPostProcessorProvider = (*PDFPostProcessors)(nil)
+)
@@ -0,0 +1,41 @@
+package pdfpostprocessors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+)
+
+func TestPDFPostProcessorUnknownName(t *testing.T) {
+	mod := new(PDFPostProcessors)
+
+	_, err := mod.PDFPostProcessor("does-not-exist")
+	if !errors.Is(err, gotenberg.ErrPDFPostProcessorNotAvailable) {
+		t.Fatalf("expected ErrPDFPostProcessorNotAvailable, got %v", err)
+	}
+}
+
+// TestPDFPostProcessorSignRedactUnavailable asserts that "sign" and
+// "redact" - requested post-processors with no implementation yet - are
+// reported the same way as any other unknown processor, rather than
+// reaching an unreachable stub.
+func TestPDFPostProcessorSignRedactUnavailable(t *testing.T) {
+	mod := new(PDFPostProcessors)
+
+	for _, name := range []string{"sign", "redact"} {
+		_, err := mod.PDFPostProcessor(name)
+		if !errors.Is(err, gotenberg.ErrPDFPostProcessorNotAvailable) {
+			t.Fatalf("%s: expected ErrPDFPostProcessorNotAvailable, got %v", name, err)
+		}
+	}
+}
+
+func TestWatermarkProcessorRequiresTextOrImage(t *testing.T) {
+	p := watermarkProcessor{pdfcpuBinPath: "/bin/true"}
+
+	err := p.Process(nil, nil, "", "", map[string]string{})
+	if err == nil {
+		t.Fatal("expected an error when neither watermarkText nor watermarkImage is set")
+	}
+}
@@ -0,0 +1,135 @@
+package pdfpostprocessors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+	"go.uber.org/zap"
+)
+
+// watermarkProcessor overlays text or an image onto every page of a PDF via
+// pdfcpu's watermark command.
+type watermarkProcessor struct {
+	pdfcpuBinPath string
+}
+
+func (p watermarkProcessor) Process(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, params map[string]string) error {
+	text := params["watermarkText"]
+	image := params["watermarkImage"]
+
+	if text == "" && image == "" {
+		return errors.New("watermark: one of 'watermarkText' or 'watermarkImage' is required")
+	}
+
+	mode := "text"
+	content := text
+	if image != "" {
+		mode = "image"
+		content = image
+	}
+
+	opacity := params["watermarkOpacity"]
+	if opacity == "" {
+		opacity = "0.5"
+	}
+
+	args := []string{"watermark", "add", "-mode", mode, content, fmt.Sprintf("opacity:%s", opacity), inputPath, outputPath}
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, p.pdfcpuBinPath, args...)
+	if err != nil {
+		return fmt.Errorf("create pdfcpu command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		return fmt.Errorf("pdfcpu watermark: %w", err)
+	}
+
+	return nil
+}
+
+// encryptProcessor applies owner/user passwords and permission flags to a
+// PDF via qpdf.
+type encryptProcessor struct {
+	qpdfBinPath string
+}
+
+func (p encryptProcessor) Process(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, params map[string]string) error {
+	ownerPassword := params["ownerPassword"]
+	userPassword := params["userPassword"]
+
+	if ownerPassword == "" && userPassword == "" {
+		return errors.New("encrypt: one of 'ownerPassword' or 'userPassword' is required")
+	}
+
+	permissions := params["permissions"]
+	if permissions == "" {
+		permissions = "--print=full --modify=none"
+	}
+
+	args := []string{"--encrypt", userPassword, ownerPassword, "256"}
+	args = append(args, strings.Fields(permissions)...)
+	args = append(args, "--", inputPath, outputPath)
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, p.qpdfBinPath, args...)
+	if err != nil {
+		return fmt.Errorf("create qpdf command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		return fmt.Errorf("qpdf encrypt: %w", err)
+	}
+
+	return nil
+}
+
+// attachProcessor embeds additional files into a PDF via pdfcpu.
+type attachProcessor struct {
+	pdfcpuBinPath string
+}
+
+func (p attachProcessor) Process(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, params map[string]string) error {
+	attachments := params["attachments"]
+	if attachments == "" {
+		return errors.New("attach: 'attachments' is required")
+	}
+
+	args := append([]string{"attachments", "add", inputPath, outputPath}, strings.Fields(attachments)...)
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, p.pdfcpuBinPath, args...)
+	if err != nil {
+		return fmt.Errorf("create pdfcpu command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		return fmt.Errorf("pdfcpu attachments add: %w", err)
+	}
+
+	return nil
+}
+
+// optimizeProcessor runs pdfcpu's optimize command on a PDF. It does not
+// linearize the file or downsample its images, and it does not read the
+// optimizeImageQuality param.
+type optimizeProcessor struct {
+	pdfcpuBinPath string
+}
+
+func (p optimizeProcessor) Process(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, params map[string]string) error {
+	cmd, err := gotenberg.CommandContext(ctx, logger, p.pdfcpuBinPath, "optimize", inputPath, outputPath)
+	if err != nil {
+		return fmt.Errorf("create pdfcpu command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		return fmt.Errorf("pdfcpu optimize: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package templates
+
+import "sync"
+
+// Index is a read-mostly, hot-swappable view of the templates currently
+// available under the watched directory. Rebuilds triggered by the watcher
+// replace the underlying map atomically so readers never observe a
+// half-built index.
+type Index struct {
+	mu    sync.RWMutex
+	byName map[string]Template
+}
+
+func newIndex() *Index {
+	return &Index{byName: make(map[string]Template)}
+}
+
+// Resolve returns the Template registered under name, or
+// ErrTemplateNotFound.
+func (idx *Index) Resolve(name string) (Template, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	template, ok := idx.byName[name]
+	if !ok {
+		return Template{}, ErrTemplateNotFound
+	}
+
+	return template, nil
+}
+
+// replace swaps in a freshly-built set of templates, without ever exposing
+// a partially-rebuilt index to concurrent readers.
+func (idx *Index) replace(byName map[string]Template) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.byName = byName
+}
@@ -0,0 +1,77 @@
+package templates
+
+import (
+	"fmt"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Templates))
+}
+
+// Templates is a module which watches a host directory of reusable
+// LibreOffice templates, fonts and macro libraries, and exposes them to
+// routes through a Provider.
+type Templates struct {
+	dir  string
+	stop chan struct{}
+	idx  *Index
+}
+
+// Provider is implemented by the Templates module so that routes can
+// resolve a template by name via dependency injection.
+type Provider interface {
+	TemplateIndex() *Index
+}
+
+// Descriptor returns a Templates' module descriptor.
+func (mod *Templates) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: func() string { return "templates" },
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("templates", flag.ExitOnError)
+			fs.String("templates-dir", "", "Directory watched for reusable LibreOffice templates, fonts and macro libraries; disabled when empty")
+
+			return fs
+		},
+		New: func() gotenberg.Module { return new(Templates) },
+	}
+}
+
+func (mod *Templates) Provision(ctx *gotenberg.Context) error {
+	mod.dir = ctx.ParsedFlags().MustString("templates-dir")
+	mod.stop = make(chan struct{})
+	mod.idx = newIndex()
+
+	if mod.dir == "" {
+		return nil
+	}
+
+	err := watch(ctx.Logger(), mod.dir, mod.idx, mod.stop)
+	if err != nil {
+		return fmt.Errorf("watch templates directory: %w", err)
+	}
+
+	return nil
+}
+
+// TemplateIndex returns the hot-reloadable Index built from the watched
+// directory.
+func (mod *Templates) TemplateIndex() *Index {
+	return mod.idx
+}
+
+func (mod *Templates) Stop() error {
+	close(mod.stop)
+	return nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Templates)(nil)
+	_ gotenberg.Provisioner = (*Templates)(nil)
+	_ Provider              = (*Templates)(nil)
+)
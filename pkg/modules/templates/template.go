@@ -0,0 +1,113 @@
+// Package templates watches a host directory of reusable LibreOffice
+// templates, fonts and macro libraries, and exposes them to routes such as
+// the LibreOffice convert route's "template" form field.
+package templates
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Template describes a single entry discovered under the watched directory:
+// a document (or asset bundle) plus the metadata parsed from its sidecar
+// YAML file, if any.
+type Template struct {
+	Name     string
+	Path     string
+	Metadata map[string]string
+
+	// ContentHash is the hex-encoded SHA-256 of the template file's bytes
+	// as of the last index build. Name alone doesn't change when an
+	// operator edits a template in place, so anything deriving a cache key
+	// from a Template (see the libreoffice package's conversion cache)
+	// needs this to notice the hot-reloaded content change.
+	ContentHash string
+}
+
+// sidecarExtension is the extension of the YAML file describing a
+// template's metadata, expected next to the template file itself
+// (report.odt + report.yaml).
+const sidecarExtension = ".yaml"
+
+// ErrTemplateNotFound happens when no template is registered under the
+// requested name.
+var ErrTemplateNotFound = errors.New("template not found")
+
+// buildIndex walks dir and returns every template it finds, keyed by its
+// file name without extension.
+func buildIndex(dir string) (map[string]Template, error) {
+	index := make(map[string]Template)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return index, nil
+		}
+
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), sidecarExtension) {
+			continue
+		}
+
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		path := filepath.Join(dir, entry.Name())
+
+		metadata, err := readSidecar(filepath.Join(dir, name+sidecarExtension))
+		if err != nil {
+			return nil, err
+		}
+
+		contentHash, err := hashFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		index[name] = Template{Name: name, Path: path, Metadata: metadata, ContentHash: contentHash}
+	}
+
+	return index, nil
+}
+
+// hashFile returns the hex-encoded SHA-256 of path's contents.
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read template file: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// readSidecar parses the optional YAML file describing a template. A
+// missing sidecar is not an error: the template simply has no metadata.
+func readSidecar(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var metadata map[string]string
+
+	err = yaml.Unmarshal(data, &metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,41 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildIndexContentHashChangesWithContent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "letterhead.odt")
+
+	if err := os.WriteFile(path, []byte("version one"), 0o644); err != nil {
+		t.Fatalf("write template file: %v", err)
+	}
+
+	index, err := buildIndex(dir)
+	if err != nil {
+		t.Fatalf("build index: %v", err)
+	}
+
+	hashA := index["letterhead"].ContentHash
+	if hashA == "" {
+		t.Fatal("expected a non-empty content hash")
+	}
+
+	if err := os.WriteFile(path, []byte("version two"), 0o644); err != nil {
+		t.Fatalf("rewrite template file: %v", err)
+	}
+
+	index, err = buildIndex(dir)
+	if err != nil {
+		t.Fatalf("rebuild index: %v", err)
+	}
+
+	hashB := index["letterhead"].ContentHash
+
+	if hashA == hashB {
+		t.Fatal("expected content hash to change when the template's content changes, even though its name didn't")
+	}
+}
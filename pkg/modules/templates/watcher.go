@@ -0,0 +1,90 @@
+package templates
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// debounceInterval bounds how often a burst of filesystem events (e.g. an
+// editor performing several writes while saving a single file) triggers a
+// rebuild of the index.
+const debounceInterval = 500 * time.Millisecond
+
+// watch starts an fsnotify watcher on dir and keeps idx in sync with its
+// content until stop is closed. The first index build happens
+// synchronously so that callers can rely on idx being populated once watch
+// returns.
+func watch(logger *zap.Logger, dir string, idx *Index, stop <-chan struct{}) error {
+	byName, err := buildIndex(dir)
+	if err != nil {
+		return fmt.Errorf("build initial template index: %w", err)
+	}
+
+	idx.replace(byName)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create fsnotify watcher: %w", err)
+	}
+
+	err = watcher.Add(dir)
+	if err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("watch template directory: %w", err)
+	}
+
+	go func() {
+		defer func() {
+			_ = watcher.Close()
+		}()
+
+		var debounce *time.Timer
+
+		rebuild := func() {
+			byName, err := buildIndex(dir)
+			if err != nil {
+				logger.Error(fmt.Sprintf("rebuild template index: %v", err))
+				return
+			}
+
+			idx.replace(byName)
+			logger.Debug(fmt.Sprintf("reloaded %d template(s) from %s", len(byName), dir))
+		}
+
+		for {
+			select {
+			case <-stop:
+				if debounce != nil {
+					debounce.Stop()
+				}
+
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+					continue
+				}
+
+				if debounce != nil {
+					debounce.Stop()
+				}
+
+				debounce = time.AfterFunc(debounceInterval, rebuild)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				logger.Error(fmt.Sprintf("template directory watcher: %v", err))
+			}
+		}
+	}()
+
+	return nil
+}